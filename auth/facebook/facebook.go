@@ -24,6 +24,9 @@ type TokenResponse struct {
 type Config struct {
 	Scopes []string `json:"scopes"`
 	URL    string   `json:"url"`
+
+	// HTTPClient sends the Graph API requests, defaults to http.DefaultClient
+	HTTPClient *http.Client `json:"-"`
 }
 
 // New new
@@ -40,6 +43,12 @@ func (c *Config) WithURL(url string) *Config {
 	return c
 }
 
+// WithHTTPClient override http client, e.g. logger.HTTPClient() to log the Graph API calls
+func (c *Config) WithHTTPClient(client *http.Client) *Config {
+	c.HTTPClient = client
+	return c
+}
+
 // WithScopes override scopes
 func (c *Config) WithScopes(scopes []string) *Config {
 	c.Scopes = scopes
@@ -60,7 +69,11 @@ func (c *Config) Login(accessToken string) (*TokenResponse, error) {
 	}
 	var scopes = strings.Join(c.Scopes, ",")
 	var url = fmt.Sprintf("%s/me?fields=%s&access_token=%s", c.URL, scopes, url.QueryEscape(accessToken))
-	resp, err := http.Get(url)
+	var client = http.DefaultClient
+	if c.HTTPClient != nil {
+		client = c.HTTPClient
+	}
+	resp, err := client.Get(url)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,112 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+)
+
+// logFields logs msg with fields, used by the adapters which resolve the caller themselves
+func (l *Logger) logFields(ctx context.Context, level LogLevel, caller, msg string, fields []Field) {
+	if !l.Enabled(level) {
+		return
+	}
+
+	var task = l.adapterTask(level, caller, msg, fields)
+	if ctx != nil {
+		task.withContext(ctx)
+	}
+	l.enqueue(task)
+}
+
+func (l *Logger) adapterTask(level LogLevel, caller, msg string, fields []Field) *logTask {
+	var values = make([]interface{}, 0, len(fields)+1)
+	if msg != "" || len(fields) == 0 {
+		values = append(values, msg)
+	}
+	for _, field := range fields {
+		values = append(values, field)
+	}
+
+	return l.buildlog(level, caller, valueTypeInterface, "", values...)
+}
+
+// errorField field of err, the error detail keeps its chain and stack trace
+func errorField(key string, err error) Field {
+	if err == nil {
+		return Any(key, nil)
+	}
+
+	return Any(key, NewErrorDetail(err))
+}
+
+// StdWriter io.Writer logging every line written by a std log.Logger
+type StdWriter struct {
+	logger *Logger
+	level  LogLevel
+}
+
+// Writer io.Writer for the std log package logging lines at level, e.g. log.New(l.Writer(Info), "", 0)
+func (l *Logger) Writer(level LogLevel) *StdWriter {
+	return &StdWriter{logger: l, level: level}
+}
+
+// Write implements io.Writer
+func (w *StdWriter) Write(p []byte) (int, error) {
+	var caller = callerOutside(2, "log")
+	for _, line := range bytes.Split(bytes.TrimRight(p, "\r\n"), []byte("\n")) {
+		w.logger.logFields(nil, w.level, caller, string(line), nil)
+	}
+
+	return len(p), nil
+}
+
+// StdLogger std log.Logger writing to l at level
+func (l *Logger) StdLogger(level LogLevel) *log.Logger {
+	return log.New(l.Writer(level), "", 0)
+}
+
+// RedirectStdLog redirects the output of the std log package to l at level, the returned func restores it
+func (l *Logger) RedirectStdLog(level LogLevel) func() {
+	var flags = log.Flags()
+	var prefix = log.Prefix()
+	var writer = log.Writer()
+
+	log.SetFlags(0)
+	log.SetPrefix("")
+	log.SetOutput(l.Writer(level))
+
+	return func() {
+		log.SetFlags(flags)
+		log.SetPrefix(prefix)
+		log.SetOutput(writer)
+	}
+}
+
+var _ io.Writer = (*StdWriter)(nil)
+
+// keyValueFields fields of alternating keys and values, a key without value gets nil
+func keyValueFields(keysAndValues []interface{}) []Field {
+	var fields = make([]Field, 0, (len(keysAndValues)+1)/2)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		var key, ok = keysAndValues[i].(string)
+		if !ok {
+			key = fmt.Sprint(keysAndValues[i])
+		}
+
+		var value interface{}
+		if i+1 < len(keysAndValues) {
+			value = keysAndValues[i+1]
+		}
+
+		if err, ok := value.(error); ok {
+			fields = append(fields, errorField(key, err))
+			continue
+		}
+		fields = append(fields, Any(key, value))
+	}
+
+	return fields
+}
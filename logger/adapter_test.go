@@ -0,0 +1,115 @@
+package logger
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func nextJSON(t *testing.T, writer *captureWriter) map[string]interface{} {
+	var object map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(writer.next(t)), &object))
+	return object
+}
+
+func TestStdWriter(t *testing.T) {
+	var writer = newCaptureWriter()
+	var logger = New(&Config{Writer: writer})
+	defer logger.Close()
+
+	var std = log.New(logger.Writer(Warn), "", 0)
+	var expected = nextLine()
+	std.Printf("disk %d%% full", 90)
+	assert.Regexp(t, `WARN `+expected+` logger\.TestStdWriter disk 90% full $`, writer.next(t))
+
+	var restore = logger.RedirectStdLog(Error)
+	log.Print("first\nsecond")
+	restore()
+	assert.Regexp(t, `ERROR .* first $`, writer.next(t))
+	assert.Regexp(t, `ERROR .* second $`, writer.next(t))
+}
+
+func TestLogr(t *testing.T) {
+	var writer = newCaptureWriter()
+	var logger = New(&Config{Writer: writer, Format: FormatJSON, Level: Info})
+	defer logger.Close()
+
+	var logr = logger.Logr().WithName("controller").WithValues("kind", "Pod")
+	logr.V(1).Info("skipped")
+
+	var expected = nextLine()
+	logr.Info("reconciled", "name", "web", "password", "secret")
+	var object = nextJSON(t, writer)
+	assert.Equal(t, "INFO", object["level"])
+	assert.Equal(t, "controller", object["logger"])
+	assert.Equal(t, "[controller] reconciled", object["message"])
+	assert.Equal(t, "Pod", object["kind"])
+	assert.Equal(t, "web", object["name"])
+	assert.Equal(t, RedactedMask, object["password"])
+	assert.Contains(t, object["caller"], expected)
+
+	logr.Error(errors.New("boom"), "failed", "attempt")
+	object = nextJSON(t, writer)
+	assert.Equal(t, "ERROR", object["level"])
+	assert.Equal(t, "boom", object["error"].(map[string]interface{})["message"])
+	assert.Contains(t, object, "attempt")
+}
+
+func TestZap(t *testing.T) {
+	var writer = newCaptureWriter()
+	var logger = New(&Config{Writer: writer, Format: FormatJSON})
+	defer logger.Close()
+
+	var z = logger.Zap().Named("payments").With(zap.String("service", "api"))
+
+	var expected = nextLine()
+	z.Warn("charge failed", zap.Int("amount", 10), zap.Namespace("card"), zap.String("brand", "visa"), zap.Error(errors.New("declined")))
+	var object = nextJSON(t, writer)
+	assert.Equal(t, "WARN", object["level"])
+	assert.Equal(t, "payments", object["logger"])
+	assert.Equal(t, "[payments] charge failed", object["message"])
+	assert.Equal(t, "api", object["service"])
+	assert.Equal(t, float64(10), object["amount"])
+	assert.Equal(t, "visa", object["card.brand"])
+	assert.Equal(t, "declined", object["card.error"].(map[string]interface{})["message"])
+	assert.Contains(t, object["caller"], expected)
+
+	z.WithOptions(zap.AddCaller()).Debug("cached")
+	object = nextJSON(t, writer)
+	assert.Equal(t, "DEBUG", object["level"])
+	assert.Contains(t, object["caller"], "logger/adapter_test.go:")
+}
+
+type slowSink struct {
+	*RingBuffer
+}
+
+func (s slowSink) Write(entry *Entry) error {
+	time.Sleep(time.Millisecond)
+	return s.RingBuffer.Write(entry)
+}
+
+func TestZapSync(t *testing.T) {
+	var sink = slowSink{NewRingBuffer(100)}
+	var logger = New(&Config{BufferedSize: 100, Sinks: []Sink{sink}, Console: log.New(ioutil.Discard, "", 0)})
+	defer logger.Close()
+
+	var z = logger.Zap()
+	for i := 0; i < 50; i++ {
+		z.Info("queued", zap.Int("i", i))
+	}
+
+	// Sync waits for the queue without closing the logger
+	assert.NoError(t, z.Sync())
+	assert.Len(t, sink.Entries(nil), 50)
+
+	z.Info("after sync")
+	assert.NoError(t, z.Sync())
+	assert.Len(t, sink.Entries(nil), 51)
+}
@@ -0,0 +1,371 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/thaitanloi365/gocore/logger"
+)
+
+// ErrClosed returned by Log after Close
+var ErrClosed = errors.New("audit: logger is closed")
+
+// Config audit logger config
+type Config struct {
+	// Filename append-only file of the hash chain, the chain continues when the file exists
+	Filename string
+	// Writer receives the lines instead of Filename
+	Writer io.Writer
+	// Key signs the hashes with HMAC-SHA256 so the chain can't be recomputed without it
+	Key []byte
+
+	// Sinks also receive every entry, e.g. to ship the audit trail with the other logs
+	Sinks []logger.Sink
+	// Redactor masks sensitive changed fields and metadata, defaults to logger.DefaultRedactFields
+	Redactor *logger.Redactor
+}
+
+// Event admin action to audit
+type Event struct {
+	// Actor defaults to the user ID of the request
+	Actor      string
+	Action     string
+	Resource   string
+	ResourceID string
+	// Before and After states of the resource, only the changed fields are kept
+	Before   interface{}
+	After    interface{}
+	Metadata map[string]interface{}
+}
+
+// Entry audit entry, the hash chain covers its exact json
+type Entry struct {
+	Seq        uint64                 `json:"seq"`
+	Time       time.Time              `json:"time"`
+	Actor      string                 `json:"actor"`
+	Action     string                 `json:"action"`
+	Resource   string                 `json:"resource"`
+	ResourceID string                 `json:"resource_id,omitempty"`
+	Changes    []Change               `json:"changes,omitempty"`
+	Request    *logger.EntryRequest   `json:"request,omitempty"`
+	Metadata   map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// Line of the audit file, hash is computed from prev_hash and the raw entry
+type Line struct {
+	Entry    json.RawMessage `json:"entry"`
+	PrevHash string          `json:"prev_hash"`
+	Hash     string          `json:"hash"`
+}
+
+// Logger append-only audit logger, every line includes the hash of the previous one
+type Logger struct {
+	mutex    sync.Mutex
+	config   *Config
+	writer   io.Writer
+	file     *os.File
+	redactor *logger.Redactor
+	seq      uint64
+	prevHash string
+	closed   bool
+
+	now func() time.Time
+}
+
+// New audit logger, opens Filename in append mode and continues its chain
+func New(config *Config) (*Logger, error) {
+	var a = &Logger{
+		config:   config,
+		writer:   config.Writer,
+		redactor: config.Redactor,
+		now:      time.Now,
+	}
+
+	if a.redactor == nil {
+		a.redactor = logger.NewRedactor(logger.DefaultRedactFields)
+	}
+
+	if a.writer == nil {
+		if config.Filename == "" {
+			return nil, errors.New("audit: Filename or Writer is required")
+		}
+
+		file, err := os.OpenFile(config.Filename, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0600)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := a.resume(file); err != nil {
+			file.Close()
+			return nil, err
+		}
+
+		a.file = file
+		a.writer = file
+	}
+
+	return a, nil
+}
+
+// Log appends the event, request info and the actor default are read from ctx
+func (a *Logger) Log(ctx context.Context, event *Event) (*Entry, error) {
+	var entry = &Entry{
+		Time:       a.now(),
+		Actor:      event.Actor,
+		Action:     event.Action,
+		Resource:   event.Resource,
+		ResourceID: event.ResourceID,
+		Changes:    a.redactChanges(Diff(event.Before, event.After)),
+	}
+
+	if len(event.Metadata) > 0 {
+		entry.Metadata = a.redactMetadata(event.Metadata)
+	}
+
+	if info := logger.FromContext(ctx); info != nil {
+		entry.Request = &logger.EntryRequest{
+			ID:         info.RequestID,
+			UserID:     info.UserID,
+			RefErrorID: info.RefErrorID,
+			Method:     info.Method,
+			URI:        info.URI,
+			TraceID:    info.TraceID,
+			SpanID:     info.SpanID,
+		}
+		if entry.Actor == "" {
+			entry.Actor = info.UserID
+		}
+	}
+
+	line, err := a.append(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sink := range a.config.Sinks {
+		if err := sink.Write(sinkEntry(entry, line)); err != nil {
+			fmt.Fprintf(os.Stderr, "audit: write sink error %v\n", err)
+		}
+	}
+
+	return entry, nil
+}
+
+// LogWithEchoContext appends the event with the request info of c
+func (a *Logger) LogWithEchoContext(c echo.Context, event *Event) (*Entry, error) {
+	var ctx = c.Request().Context()
+	if logger.FromContext(ctx) == nil {
+		var req = c.Request()
+		var info = &logger.ContextInfo{
+			RequestID: req.Header.Get(echo.HeaderXRequestID),
+			Method:    req.Method,
+			URI:       req.RequestURI,
+		}
+		if info.RequestID == "" {
+			info.RequestID = c.Response().Header().Get(echo.HeaderXRequestID)
+		}
+		if id, ok := c.Get(logger.UserIDKey).(string); ok {
+			info.UserID = id
+		}
+		ctx = logger.WithContext(ctx, info)
+	}
+
+	return a.Log(ctx, event)
+}
+
+// Head sequence and hash of the last entry, store them elsewhere to detect deleted trailing lines
+func (a *Logger) Head() (uint64, string) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	return a.seq, a.prevHash
+}
+
+// Close closes the file and the sinks
+func (a *Logger) Close() error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.closed {
+		return nil
+	}
+	a.closed = true
+
+	var err error
+	for _, sink := range a.config.Sinks {
+		if e := sink.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+
+	if a.file != nil {
+		if e := a.file.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+
+	return err
+}
+
+func (a *Logger) append(entry *Entry) (*Line, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.closed {
+		return nil, ErrClosed
+	}
+
+	entry.Seq = a.seq + 1
+	raw, err := json.Marshal(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	var line = &Line{
+		Entry:    raw,
+		PrevHash: a.prevHash,
+		Hash:     computeHash(a.config.Key, a.prevHash, raw),
+	}
+
+	data, err := json.Marshal(line)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := a.writer.Write(append(data, '\n')); err != nil {
+		return nil, err
+	}
+
+	if a.file != nil {
+		if err := a.file.Sync(); err != nil {
+			return nil, err
+		}
+	}
+
+	a.seq = entry.Seq
+	a.prevHash = line.Hash
+
+	return line, nil
+}
+
+// resume reads the head of the chain from the last line of the file
+func (a *Logger) resume(file *os.File) error {
+	var reader = bufio.NewReader(file)
+	var last []byte
+	for {
+		data, err := reader.ReadBytes('\n')
+		if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 {
+			last = trimmed
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if last == nil {
+		return nil
+	}
+
+	var line Line
+	var entry Entry
+	if err := json.Unmarshal(last, &line); err != nil {
+		return fmt.Errorf("audit: invalid last line of %s: %w", a.config.Filename, err)
+	}
+	if err := json.Unmarshal(line.Entry, &entry); err != nil {
+		return fmt.Errorf("audit: invalid last entry of %s: %w", a.config.Filename, err)
+	}
+
+	a.seq = entry.Seq
+	a.prevHash = line.Hash
+
+	return nil
+}
+
+func (a *Logger) redactChanges(changes []Change) []Change {
+	for i, change := range changes {
+		if a.redactor.IsSensitive(change.Field) {
+			if change.Before != nil {
+				changes[i].Before = logger.RedactedMask
+			}
+			if change.After != nil {
+				changes[i].After = logger.RedactedMask
+			}
+		}
+	}
+
+	return changes
+}
+
+func (a *Logger) redactMetadata(metadata map[string]interface{}) map[string]interface{} {
+	decoded, ok := decode(metadata).(map[string]interface{})
+	if !ok {
+		return metadata
+	}
+
+	return a.redactor.RedactValue(decoded).(map[string]interface{})
+}
+
+func sinkEntry(entry *Entry, line *Line) *logger.Entry {
+	var message = []string{entry.Action, entry.Resource}
+	if entry.ResourceID != "" {
+		message = append(message, entry.ResourceID)
+	}
+
+	var fields = []logger.Field{
+		logger.Any("audit_seq", entry.Seq),
+		logger.Any("actor", entry.Actor),
+		logger.Any("action", entry.Action),
+		logger.Any("resource", entry.Resource),
+	}
+	if entry.ResourceID != "" {
+		fields = append(fields, logger.Any("resource_id", entry.ResourceID))
+	}
+	if len(entry.Changes) > 0 {
+		fields = append(fields, logger.Any("changes", entry.Changes))
+	}
+	if len(entry.Metadata) > 0 {
+		fields = append(fields, logger.Any("metadata", entry.Metadata))
+	}
+	fields = append(fields, logger.Any("audit_hash", line.Hash))
+
+	return &logger.Entry{
+		Level:   logger.Info,
+		Logger:  "audit",
+		Time:    entry.Time,
+		Message: strings.Join(message, " "),
+		Fields:  fields,
+		Request: entry.Request,
+	}
+}
+
+func computeHash(key []byte, prevHash string, entry []byte) string {
+	var h hash.Hash
+	if len(key) > 0 {
+		h = hmac.New(sha256.New, key)
+	} else {
+		h = sha256.New()
+	}
+
+	h.Write([]byte(prevHash))
+	h.Write([]byte{'\n'})
+	h.Write(entry)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,152 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/thaitanloi365/gocore/logger"
+	"github.com/thaitanloi365/gocore/logger/logtest"
+)
+
+type user struct {
+	Name     string            `json:"name"`
+	Role     string            `json:"role"`
+	Password string            `json:"password"`
+	Address  map[string]string `json:"address,omitempty"`
+}
+
+func writeEntries(t *testing.T, path string, key []byte) {
+	audit, err := New(&Config{Filename: path, Key: key})
+	assert.NoError(t, err)
+	defer audit.Close()
+
+	var ctx = logger.WithContext(context.Background(), &logger.ContextInfo{RequestID: "req-1", UserID: "admin-1"})
+	for _, role := range []string{"editor", "admin", "owner"} {
+		_, err := audit.Log(ctx, &Event{Action: "update_role", Resource: "user", ResourceID: "42", After: map[string]string{"role": role}})
+		assert.NoError(t, err)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	var path = filepath.Join(t.TempDir(), "audit.log")
+	var key = []byte("secret")
+	writeEntries(t, path, key)
+
+	result, err := VerifyFile(path, key)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.Entries)
+	assert.Equal(t, uint64(3), result.LastSeq)
+
+	// The chain continues after reopening
+	audit, err := New(&Config{Filename: path, Key: key})
+	assert.NoError(t, err)
+	seq, hash := audit.Head()
+	assert.Equal(t, uint64(3), seq)
+	assert.Equal(t, result.LastHash, hash)
+	_, err = audit.Log(context.Background(), &Event{Actor: "admin-2", Action: "delete", Resource: "user", ResourceID: "42"})
+	assert.NoError(t, err)
+	assert.NoError(t, audit.Close())
+
+	result, err = VerifyFile(path, key)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, result.Entries)
+
+	_, err = VerifyFile(path, []byte("other"))
+	assert.EqualError(t, err, "audit: line 1: hash mismatch, the entry was edited")
+}
+
+func TestVerifyTampered(t *testing.T) {
+	var path = filepath.Join(t.TempDir(), "audit.log")
+	writeEntries(t, path, nil)
+
+	data, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	var lines = strings.SplitAfter(strings.TrimSpace(string(data)), "\n")
+	assert.Len(t, lines, 3)
+
+	var edited = strings.Replace(lines[1], `"actor":"admin-1"`, `"actor":"admin-2"`, 1)
+	_, err = Verify(strings.NewReader(lines[0]+edited+lines[2]), nil)
+	assert.EqualError(t, err, "audit: line 2: hash mismatch, the entry was edited")
+
+	_, err = Verify(strings.NewReader(lines[0]+lines[2]), nil)
+	assert.EqualError(t, err, "audit: line 2: previous hash mismatch, a line before was deleted or reordered")
+
+	_, err = Verify(strings.NewReader(lines[0]+"{not json\n"), nil)
+	assert.EqualError(t, err, "audit: line 2: invalid json, the line was edited")
+
+	result, err := Verify(strings.NewReader(lines[0]+lines[1]), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Entries)
+}
+
+func TestLogEntry(t *testing.T) {
+	var buf bytes.Buffer
+	var _, sink = logtest.New(t)
+	audit, err := New(&Config{Writer: &buf, Sinks: []logger.Sink{sink}})
+	assert.NoError(t, err)
+	audit.now = func() time.Time { return time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC) }
+
+	var e = echo.New()
+	var req = httptest.NewRequest(http.MethodPut, "/users/42", nil)
+	req.Header.Set(echo.HeaderXRequestID, "req-9")
+	var c = e.NewContext(req, httptest.NewRecorder())
+	c.Set(logger.UserIDKey, "admin-1")
+
+	entry, err := audit.LogWithEchoContext(c, &Event{
+		Action:     "update",
+		Resource:   "user",
+		ResourceID: "42",
+		Before:     user{Name: "alice", Role: "editor", Password: "old", Address: map[string]string{"city": "Hanoi"}},
+		After:      user{Name: "alice", Role: "admin", Password: "new"},
+		Metadata:   map[string]interface{}{"reason": "promotion", "token": "abc"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "admin-1", entry.Actor)
+	assert.Equal(t, "req-9", entry.Request.ID)
+	assert.Equal(t, []Change{
+		{Field: "address.city", Before: "Hanoi", After: nil},
+		{Field: "password", Before: logger.RedactedMask, After: logger.RedactedMask},
+		{Field: "role", Before: "editor", After: "admin"},
+	}, entry.Changes)
+	assert.Equal(t, logger.RedactedMask, entry.Metadata["token"])
+
+	var line Line
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, "", line.PrevHash)
+	assert.JSONEq(t, `{
+		"seq": 1,
+		"time": "2022-01-01T00:00:00Z",
+		"actor": "admin-1",
+		"action": "update",
+		"resource": "user",
+		"resource_id": "42",
+		"changes": [
+			{"field": "address.city", "before": "Hanoi", "after": null},
+			{"field": "password", "before": "[REDACTED]", "after": "[REDACTED]"},
+			{"field": "role", "before": "editor", "after": "admin"}
+		],
+		"request": {"id": "req-9", "user_id": "admin-1", "method": "PUT", "uri": "/users/42"},
+		"metadata": {"reason": "promotion", "token": "[REDACTED]"}
+	}`, string(line.Entry))
+
+	sink.AssertLogged(t, logger.Info, "update user 42")
+	sink.AssertField(t, "audit_hash", line.Hash)
+	assert.Equal(t, "audit", sink.Entries()[0].Logger)
+}
+
+func TestDiff(t *testing.T) {
+	assert.Equal(t, []Change{}, Diff(map[string]int{"a": 1}, map[string]int{"a": 1}))
+	assert.Equal(t, []Change{{Field: "a", Before: nil, After: json.Number("1")}}, Diff(nil, map[string]int{"a": 1}))
+	assert.Equal(t, []Change{{Field: "tags", Before: []interface{}{"a"}, After: []interface{}{"a", "b"}}},
+		Diff(map[string][]string{"tags": {"a"}}, map[string][]string{"tags": {"a", "b"}}))
+}
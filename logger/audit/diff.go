@@ -0,0 +1,87 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+// Change field changed between the before and after states
+type Change struct {
+	// Field dotted path of the field, e.g. address.city
+	Field  string      `json:"field"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// Diff changed fields of two values encoded as json, nested objects are compared field by field
+// and arrays as a whole. Added fields have a nil Before, removed fields a nil After
+func Diff(before, after interface{}) []Change {
+	var beforeFields = map[string]interface{}{}
+	var afterFields = map[string]interface{}{}
+	flatten("", decode(before), beforeFields)
+	flatten("", decode(after), afterFields)
+
+	var keys = make([]string, 0, len(beforeFields)+len(afterFields))
+	for key := range beforeFields {
+		keys = append(keys, key)
+	}
+	for key := range afterFields {
+		if _, ok := beforeFields[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var changes = []Change{}
+	for _, key := range keys {
+		if !reflect.DeepEqual(beforeFields[key], afterFields[key]) {
+			changes = append(changes, Change{
+				Field:  key,
+				Before: beforeFields[key],
+				After:  afterFields[key],
+			})
+		}
+	}
+
+	return changes
+}
+
+// decode value as decoded json, numbers are kept as json.Number
+func decode(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+
+	var decoded interface{}
+	var decoder = json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil
+	}
+
+	return decoded
+}
+
+func flatten(prefix string, value interface{}, fields map[string]interface{}) {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		if value != nil || prefix != "" {
+			fields[prefix] = value
+		}
+		return
+	}
+
+	for key, item := range object {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		flatten(key, item, fields)
+	}
+}
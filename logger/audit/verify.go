@@ -0,0 +1,92 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// VerifyResult summary of a valid chain
+type VerifyResult struct {
+	Entries  int
+	LastSeq  uint64
+	LastHash string
+}
+
+// VerifyError first line breaking the chain
+type VerifyError struct {
+	Line   int
+	Reason string
+}
+
+func (e *VerifyError) Error() string {
+	return fmt.Sprintf("audit: line %d: %s", e.Line, e.Reason)
+}
+
+// VerifyFile verifies the chain of an audit file, see Verify
+func VerifyFile(path string, key []byte) (*VerifyResult, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return Verify(file, key)
+}
+
+// Verify verifies the chain of the audit lines read from r with the key of the logger.
+// Edited lines fail their hash, deleted or reordered lines fail the previous hash and sequence.
+// Deleted trailing lines can only be detected by comparing the result with Logger.Head stored elsewhere
+func Verify(r io.Reader, key []byte) (*VerifyResult, error) {
+	var result = &VerifyResult{}
+	var reader = bufio.NewReader(r)
+	for number := 1; ; number++ {
+		data, err := reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+
+		if data = bytes.TrimSpace(data); len(data) > 0 {
+			if e := result.verify(number, data, key); e != nil {
+				return result, e
+			}
+		}
+
+		if err == io.EOF {
+			return result, nil
+		}
+	}
+}
+
+func (result *VerifyResult) verify(number int, data []byte, key []byte) error {
+	var line Line
+	if err := json.Unmarshal(data, &line); err != nil {
+		return &VerifyError{Line: number, Reason: "invalid json, the line was edited"}
+	}
+
+	if line.PrevHash != result.LastHash {
+		return &VerifyError{Line: number, Reason: "previous hash mismatch, a line before was deleted or reordered"}
+	}
+
+	if computeHash(key, line.PrevHash, line.Entry) != line.Hash {
+		return &VerifyError{Line: number, Reason: "hash mismatch, the entry was edited"}
+	}
+
+	var entry Entry
+	if err := json.Unmarshal(line.Entry, &entry); err != nil {
+		return &VerifyError{Line: number, Reason: "invalid entry"}
+	}
+
+	if entry.Seq != result.LastSeq+1 {
+		return &VerifyError{Line: number, Reason: fmt.Sprintf("seq %d follows %d, the chain was rebuilt", entry.Seq, result.LastSeq)}
+	}
+
+	result.Entries++
+	result.LastSeq = entry.Seq
+	result.LastHash = line.Hash
+
+	return nil
+}
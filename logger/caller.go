@@ -0,0 +1,84 @@
+package logger
+
+import (
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// caller resolves the frame skip levels above the caller of caller
+func caller(skip int) string {
+	var pcs [1]uintptr
+	if runtime.Callers(skip+1, pcs[:]) == 0 {
+		return ""
+	}
+
+	frame, _ := runtime.CallersFrames(pcs[:]).Next()
+	return formatFrame(frame)
+}
+
+// callerPC resolves the frame of a program counter, e.g. slog.Record.PC
+func callerPC(pc uintptr) string {
+	if pc == 0 {
+		return ""
+	}
+
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	return formatFrame(frame)
+}
+
+// callerOutside resolves the first frame skip levels above the caller of callerOutside
+// whose function is not in one of the packages, e.g. the std log package
+func callerOutside(skip int, packages ...string) string {
+	var pcs [16]uintptr
+	var n = runtime.Callers(skip+1, pcs[:])
+	var frames = runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !inPackages(frame.Function, packages) {
+			return formatFrame(frame)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+func inPackages(function string, packages []string) bool {
+	for _, pkg := range packages {
+		if strings.HasPrefix(function, pkg+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func formatFrame(frame runtime.Frame) string {
+	if frame.File == "" {
+		return ""
+	}
+
+	var result = trimPath(frame.File) + ":" + strconv.Itoa(frame.Line)
+	if frame.Function != "" {
+		result += " " + trimFunction(frame.Function)
+	}
+
+	return result
+}
+
+// trimPath keeps the package directory and the file name
+func trimPath(file string) string {
+	var dir, name = filepath.Split(file)
+	return filepath.Join(filepath.Base(dir), name)
+}
+
+// trimFunction removes the import path but the package name
+func trimFunction(function string) string {
+	if i := strings.LastIndex(function, "/"); i >= 0 {
+		return function[i+1:]
+	}
+
+	return function
+}
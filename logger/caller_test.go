@@ -0,0 +1,56 @@
+package logger
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func nextLine() string {
+	_, file, line, _ := runtime.Caller(1)
+	return fmt.Sprintf("%s:%d", trimPath(file), line+1)
+}
+
+func logWrapped(logger *Logger, value string) {
+	logger.WithCallerSkip(1).Info(value)
+}
+
+func TestCaller(t *testing.T) {
+	var writer = newCaptureWriter()
+	var logger = New(&Config{Writer: writer})
+
+	var expected = nextLine()
+	logger.Info("direct")
+	assert.Regexp(t, fmt.Sprintf(`INFO %s logger\.TestCaller direct $`, expected), writer.next(t))
+
+	expected = nextLine()
+	logger.Debugf("format %d", 1)
+	assert.Regexp(t, fmt.Sprintf(`DEBUG %s logger\.TestCaller format 1$`, expected), writer.next(t))
+
+	expected = nextLine()
+	logWrapped(logger, "wrapped")
+	assert.Regexp(t, fmt.Sprintf(`INFO %s logger\.TestCaller wrapped $`, expected), writer.next(t))
+
+	func() {
+		expected = nextLine()
+		logger.Warn("closure")
+	}()
+	assert.Regexp(t, fmt.Sprintf(`WARN %s logger\.TestCaller\.func1 closure $`, expected), writer.next(t))
+}
+
+func TestConfigCallerSkip(t *testing.T) {
+	var writer = newCaptureWriter()
+	var logger = New(&Config{Writer: writer, CallerSkip: 1})
+
+	var expected = nextLine()
+	func() { logger.Error("skipped") }()
+	assert.Regexp(t, fmt.Sprintf(`ERROR %s logger\.TestConfigCallerSkip skipped $`, expected), writer.next(t))
+}
+
+func TestTrimPath(t *testing.T) {
+	assert.Equal(t, "logger/logger.go", trimPath("/go/src/github.com/thaitanloi365/gocore/logger/logger.go"))
+	assert.Equal(t, "main.go", trimPath("main.go"))
+	assert.Equal(t, "logger.(*Logger).Info", trimFunction("github.com/thaitanloi365/gocore/logger.(*Logger).Info"))
+}
@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+)
+
+// ConsoleFormat layout of the console output
+type ConsoleFormat string
+
+// All console formats
+const (
+	// ConsolePretty request info, time, level and caller on their own line, json values indented
+	ConsolePretty ConsoleFormat = "pretty"
+	// ConsoleCompact one line per entry like the file output, for local development
+	ConsoleCompact ConsoleFormat = "compact"
+)
+
+// Theme colors of the levels on the console, e.g. Theme{Debug: Teal, Info: Purple}
+type Theme map[LogLevel]func(...interface{}) string
+
+// DefaultTheme colors used for the levels missing from Config.Theme
+var DefaultTheme = Theme{
+	Debug: Green,
+	Info:  Blue,
+	Warn:  Yellow,
+	Error: Red,
+}
+
+// painter color of level, plain text when colors are disabled
+func (theme Theme) painter(level LogLevel, colorful bool) func(...interface{}) string {
+	if !colorful {
+		return fmt.Sprint
+	}
+
+	if paint, ok := theme[level]; ok && paint != nil {
+		return paint
+	}
+
+	return DefaultTheme[level]
+}
+
+// colorEnabled colors are written when Colorful is set, NO_COLOR is empty and the console is a terminal or ForceColor is set
+func (config *Config) colorEnabled(console Writer) bool {
+	if !config.Colorful {
+		return false
+	}
+
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+
+	return config.ForceColor || isTerminal(console)
+}
+
+// isTerminal reports whether the console writes to a terminal, writers other than
+// a *log.Logger writing to an *os.File can't be detected and are not terminals
+func isTerminal(console Writer) bool {
+	var writer io.Writer
+	switch c := console.(type) {
+	case *log.Logger:
+		writer = c.Writer()
+	case io.Writer:
+		writer = c
+	}
+
+	file, ok := writer.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,69 @@
+package logger
+
+import (
+	"bytes"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func consoleOutput(config *Config, write func(logger *Logger)) string {
+	var buf bytes.Buffer
+	config.Sync = true
+	config.Console = log.New(&buf, "", 0)
+	config.DateFormat = "15:04"
+
+	var logger = New(config)
+	write(logger)
+	logger.Close()
+
+	return buf.String()
+}
+
+func TestConsoleColors(t *testing.T) {
+	var write = func(logger *Logger) {
+		logger.Debug("hello")
+	}
+
+	// Buffers aren't terminals
+	assert.NotContains(t, consoleOutput(&Config{Colorful: true}, write), "\033[")
+
+	var output = consoleOutput(&Config{Colorful: true, ForceColor: true, Theme: Theme{Debug: Teal}}, write)
+	assert.Contains(t, output, "\033[1;36mDEBUG logger/console_test.go")
+	assert.Contains(t, output, "hello")
+
+	assert.Contains(t, consoleOutput(&Config{Colorful: true, ForceColor: true}, write), "\033[1;32mDEBUG logger/console_test.go")
+	assert.NotContains(t, consoleOutput(&Config{ForceColor: true}, write), "\033[")
+
+	os.Setenv("NO_COLOR", "1")
+	defer os.Unsetenv("NO_COLOR")
+	assert.NotContains(t, consoleOutput(&Config{Colorful: true, ForceColor: true}, write), "\033[")
+
+	assert.NotEqual(t, Blue("x"), Purple("x"))
+}
+
+func TestConsoleCompact(t *testing.T) {
+	var output = consoleOutput(&Config{ConsoleFormat: ConsoleCompact}, func(logger *Logger) {
+		logger.Info("charged", Any("order", 7))
+		logger.InfoJSON(map[string]int{"amount": 10})
+	})
+
+	var lines = strings.Split(strings.TrimSpace(output), "\n")
+	assert.Len(t, lines, 2)
+	assert.Regexp(t, `^\d\d:\d\d INFO logger/console_test.go:\d+ logger.TestConsoleCompact.func1 charged order=7 $`, lines[0])
+	assert.Regexp(t, `INFO .* \{"amount":10\}`, lines[1])
+}
+
+func TestIsTerminal(t *testing.T) {
+	file, err := ioutil.TempFile(t.TempDir(), "console")
+	assert.NoError(t, err)
+	defer file.Close()
+
+	assert.False(t, isTerminal(log.New(file, "", 0)))
+	assert.False(t, isTerminal(log.New(&bytes.Buffer{}, "", 0)))
+	assert.False(t, isTerminal(log.New(ioutil.Discard, "", 0)))
+}
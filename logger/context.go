@@ -0,0 +1,151 @@
+package logger
+
+import (
+	"context"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// HeaderTraceParent W3C trace context header
+const HeaderTraceParent = "traceparent"
+
+type contextKey struct{}
+
+// ContextInfo request scoped values carried by context.Context
+type ContextInfo struct {
+	RequestID  string
+	UserID     string
+	RefErrorID string
+	TraceID    string
+	SpanID     string
+	Method     string
+	URI        string
+}
+
+// WithContext returns a copy of ctx carrying info
+func WithContext(ctx context.Context, info *ContextInfo) context.Context {
+	return context.WithValue(ctx, contextKey{}, info)
+}
+
+// FromContext returns the info carried by ctx, nil if there is none
+func FromContext(ctx context.Context) *ContextInfo {
+	if ctx == nil {
+		return nil
+	}
+
+	info, _ := ctx.Value(contextKey{}).(*ContextInfo)
+	return info
+}
+
+// ContextMiddleware puts request ID, user ID, ref error ID and trace IDs into the request context.
+// Register it after the request ID and auth middlewares so their values are picked up, set the
+// user ID and ref error ID later with SetUserID and SetRefErrorID
+func ContextMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			var req = c.Request()
+			var info = &ContextInfo{
+				RequestID: req.Header.Get(echo.HeaderXRequestID),
+				Method:    req.Method,
+				URI:       req.RequestURI,
+			}
+
+			if info.RequestID == "" {
+				info.RequestID = c.Response().Header().Get(echo.HeaderXRequestID)
+			}
+
+			if id, ok := c.Get(UserIDKey).(string); ok {
+				info.UserID = id
+			}
+
+			if id, ok := c.Get(RefErrorIDKey).(string); ok {
+				info.RefErrorID = id
+			}
+
+			info.TraceID, info.SpanID = parseTraceParent(req.Header.Get(HeaderTraceParent))
+
+			c.SetRequest(req.WithContext(WithContext(req.Context(), info)))
+
+			return next(c)
+		}
+	}
+}
+
+// SetUserID sets the user ID of the echo context and of the info of its request context,
+// for auth middlewares and handlers running after ContextMiddleware
+func SetUserID(c echo.Context, id string) {
+	c.Set(UserIDKey, id)
+	updateContextInfo(c, func(info *ContextInfo) {
+		info.UserID = id
+	})
+}
+
+// SetRefErrorID sets the ref error ID of the echo context and of the info of its request context
+func SetRefErrorID(c echo.Context, id string) {
+	c.Set(RefErrorIDKey, id)
+	updateContextInfo(c, func(info *ContextInfo) {
+		info.RefErrorID = id
+	})
+}
+
+// updateContextInfo replaces the info of the request context with an updated copy,
+// the info may be read by goroutines started earlier
+func updateContextInfo(c echo.Context, update func(info *ContextInfo)) {
+	var req = c.Request()
+	var info = FromContext(req.Context())
+	if info == nil {
+		return
+	}
+
+	var updated = *info
+	update(&updated)
+	c.SetRequest(req.WithContext(WithContext(req.Context(), &updated)))
+}
+
+// parseTraceParent extracts trace and span IDs from a header like 00-<trace-id>-<span-id>-<flags>
+func parseTraceParent(value string) (traceID string, spanID string) {
+	var parts = strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 || len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return "", ""
+	}
+
+	return parts[1], parts[2]
+}
+
+// withContext stamps the request info and the active span of ctx
+func (task *logTask) withContext(ctx context.Context) *logTask {
+	if ctx == nil {
+		return task
+	}
+
+	task.buffer = requestBufferFromContext(ctx)
+
+	var info = FromContext(ctx)
+	var spanContext = trace.SpanContextFromContext(ctx)
+	if info == nil && !spanContext.IsValid() {
+		return task
+	}
+
+	var reqInfo = &requestInfo{status: -1}
+	if info != nil {
+		reqInfo.reqID = info.RequestID
+		reqInfo.method = info.Method
+		reqInfo.uri = info.URI
+		reqInfo.userID = info.UserID
+		reqInfo.refErrorID = info.RefErrorID
+		reqInfo.traceID = info.TraceID
+		reqInfo.spanID = info.SpanID
+	}
+
+	if spanContext.IsValid() {
+		reqInfo.traceID = spanContext.TraceID().String()
+		reqInfo.spanID = spanContext.SpanID().String()
+	}
+
+	task.withRequestInfo(reqInfo)
+	task.recordSpanEvent(ctx)
+
+	return task
+}
@@ -0,0 +1,84 @@
+package logger
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextMiddleware(t *testing.T) {
+	var e = echo.New()
+	var req = httptest.NewRequest(http.MethodGet, "/users?id=1", nil)
+	req.Header.Set(echo.HeaderXRequestID, "req-1")
+	req.Header.Set(HeaderTraceParent, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	var rec = httptest.NewRecorder()
+	var c = e.NewContext(req, rec)
+	c.Set(UserIDKey, "user-1")
+
+	var info *ContextInfo
+	var handler = ContextMiddleware()(func(c echo.Context) error {
+		info = FromContext(c.Request().Context())
+		return nil
+	})
+
+	assert.NoError(t, handler(c))
+	assert.Equal(t, &ContextInfo{
+		RequestID: "req-1",
+		UserID:    "user-1",
+		TraceID:   "4bf92f3577b34da6a3ce929d0e0e4736",
+		SpanID:    "00f067aa0ba902b7",
+		Method:    http.MethodGet,
+		URI:       "/users?id=1",
+	}, info)
+}
+
+func TestSetUserID(t *testing.T) {
+	var sink = NewRingBuffer(10)
+	var logger = New(&Config{Sync: true, Sinks: []Sink{sink}, Console: log.New(ioutil.Discard, "", 0)})
+	defer logger.Close()
+
+	var e = echo.New()
+	e.Use(ContextMiddleware())
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			var before = c.Request().Context()
+			SetUserID(c, "user-1")
+			// Contexts taken earlier keep their info
+			assert.Empty(t, FromContext(before).UserID)
+			return next(c)
+		}
+	})
+	e.GET("/", func(c echo.Context) error {
+		SetRefErrorID(c, "ref-1")
+		logger.InfoWithContext(c.Request().Context(), "hello")
+		return nil
+	})
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	var request = sink.Entries(nil)[0].Request
+	assert.Equal(t, "user-1", request.UserID)
+	assert.Equal(t, "ref-1", request.RefErrorID)
+}
+
+func TestTaskWithContext(t *testing.T) {
+	var logger = New(nil)
+	var ctx = WithContext(context.Background(), &ContextInfo{
+		RequestID:  "req-1",
+		UserID:     "user-1",
+		RefErrorID: "ref-1",
+		TraceID:    "trace-1",
+		SpanID:     "span-1",
+	})
+
+	var task = logger.buildlog(Info, "", valueTypeInterface, "", "hello").withContext(ctx)
+	assert.Equal(t, "req-1 [user-1::ref-1] -1 trace=trace-1 span=span-1", task.formatRequestInfo())
+
+	task = logger.buildlog(Info, "", valueTypeInterface, "", "hello").withContext(context.Background())
+	assert.Nil(t, task.requestInfo)
+}
@@ -0,0 +1,234 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LogFormat output format
+type LogFormat string
+
+// All formats
+const (
+	FormatText LogFormat = "text"
+	FormatJSON LogFormat = "json"
+)
+
+// Time formats understood besides time layouts, for DateFormat and the sink TimeFormat
+const (
+	TimeFormatRFC3339     = "rfc3339"
+	TimeFormatRFC3339Nano = "rfc3339nano"
+	// TimeFormatEpoch seconds since the unix epoch, a number in json
+	TimeFormatEpoch = "epoch"
+	// TimeFormatEpochMillis milliseconds since the unix epoch, a number in json
+	TimeFormatEpochMillis = "epoch_millis"
+)
+
+// FormatTime formats t with one of the TimeFormat* names or a time layout
+func FormatTime(t time.Time, format string) string {
+	switch strings.ToLower(format) {
+	case TimeFormatRFC3339:
+		return t.Format(time.RFC3339)
+	case TimeFormatRFC3339Nano:
+		return t.Format(time.RFC3339Nano)
+	case TimeFormatEpoch:
+		return strconv.FormatInt(t.Unix(), 10)
+	case TimeFormatEpochMillis:
+		return strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10)
+	}
+
+	return t.Format(format)
+}
+
+// timeValue json value of t, epoch formats are numbers
+func timeValue(t time.Time, format string) interface{} {
+	switch strings.ToLower(format) {
+	case TimeFormatEpoch:
+		return t.Unix()
+	case TimeFormatEpochMillis:
+		return t.UnixNano() / int64(time.Millisecond)
+	}
+
+	return FormatTime(t, format)
+}
+
+// Encoder encodes an entry as one line without the trailing newline
+type Encoder interface {
+	Encode(entry *Entry) []byte
+}
+
+// NewEncoder encoder of the format, text by default
+func NewEncoder(format LogFormat, timeFormat string) Encoder {
+	if format == FormatJSON {
+		return &JSONEncoder{TimeFormat: timeFormat}
+	}
+
+	return &TextEncoder{TimeFormat: timeFormat}
+}
+
+// TextEncoder encodes entries like the file writer: [request] time LEVEL caller message key=value
+type TextEncoder struct {
+	// TimeFormat time layout or one of the TimeFormat* names
+	TimeFormat string
+}
+
+// Encode implements Encoder
+func (e *TextEncoder) Encode(entry *Entry) []byte {
+	var buf bytes.Buffer
+	if entry.Request != nil {
+		if info := entry.Request.String(); info != "" {
+			buf.WriteString(info)
+			buf.WriteByte(' ')
+		}
+	}
+
+	buf.WriteString(FormatTime(entry.Time, e.TimeFormat))
+	buf.WriteByte(' ')
+	buf.WriteString(entry.Level.String())
+	if entry.Caller != "" {
+		buf.WriteByte(' ')
+		buf.WriteString(entry.Caller)
+	}
+
+	if entry.Message != "" {
+		buf.WriteByte(' ')
+		buf.WriteString(entry.Message)
+	}
+
+	for _, field := range entry.Fields {
+		buf.WriteByte(' ')
+		buf.WriteString(field.String())
+	}
+
+	if entry.Stack != "" {
+		buf.WriteByte('\n')
+		buf.WriteString(entry.Stack)
+	}
+
+	return buf.Bytes()
+}
+
+// JSONEncoder encodes entries as json lines, fields are added as top level keys
+type JSONEncoder struct {
+	// TimeFormat time layout or one of the TimeFormat* names
+	TimeFormat string
+}
+
+// Encode implements Encoder
+func (e *JSONEncoder) Encode(entry *Entry) []byte {
+	var object = map[string]interface{}{}
+	for _, field := range entry.Fields {
+		object[field.Key] = field.Value
+	}
+
+	object["time"] = timeValue(entry.Time, e.TimeFormat)
+	object["level"] = entry.Level.String()
+	if entry.Logger != "" {
+		object["logger"] = entry.Logger
+	}
+	if entry.Caller != "" {
+		object["caller"] = entry.Caller
+	}
+
+	if entry.Values != nil {
+		object["values"] = entry.Values
+	} else {
+		object["message"] = entry.Message
+	}
+
+	// Stacks of Err fields are already part of the error detail
+	if entry.Stack != "" && !entry.hasErrorStack() {
+		object["stack"] = entry.Stack
+	}
+
+	if entry.Request != nil {
+		object["request"] = entry.Request
+	}
+
+	data, err := json.Marshal(object)
+	if err != nil {
+		data, _ = json.Marshal(map[string]interface{}{
+			"time":    object["time"],
+			"level":   object["level"],
+			"message": fmt.Sprintf("Couldn't marshal log entry %v", err),
+		})
+	}
+
+	return data
+}
+
+// String request info like formatRequestInfo
+func (info *EntryRequest) String() string {
+	var parts = []string{}
+	if info.ID != "" {
+		parts = append(parts, info.ID)
+	}
+
+	var extras = []string{}
+	if info.UserID != "" {
+		extras = append(extras, info.UserID)
+	}
+
+	if info.RefErrorID != "" {
+		extras = append(extras, info.RefErrorID)
+	}
+
+	if len(extras) > 0 {
+		parts = append(parts, fmt.Sprintf("[%v]", strings.Join(extras, "::")))
+	}
+
+	if info.Status > 0 {
+		parts = append(parts, strconv.Itoa(info.Status))
+	}
+
+	if info.Method != "" {
+		parts = append(parts, info.Method, info.URI)
+	}
+
+	if info.TraceID != "" {
+		parts = append(parts, "trace="+info.TraceID)
+	}
+
+	if info.SpanID != "" {
+		parts = append(parts, "span="+info.SpanID)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// message text of the task without its fields
+func (task *logTask) message(values []interface{}) string {
+	if task.format != "" {
+		return strings.TrimSpace(fmt.Sprintf(task.format, values...))
+	}
+
+	var parts = []string{}
+	for _, value := range values {
+		if _, ok := value.(Field); ok {
+			continue
+		}
+		parts = append(parts, fmt.Sprint(value))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// fields of the task, fields passed to a format are part of the message
+func (task *logTask) fields(values []interface{}) []Field {
+	var fields = []Field{}
+	if task.format != "" {
+		return fields
+	}
+
+	for _, value := range values {
+		if field, ok := value.(Field); ok {
+			fields = append(fields, field)
+		}
+	}
+
+	return fields
+}
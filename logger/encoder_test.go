@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"bytes"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatTime(t *testing.T) {
+	var at = time.Date(2022, 1, 2, 3, 4, 5, 123456789, time.FixedZone("ICT", 7*3600))
+
+	assert.Equal(t, "2022-01-02T03:04:05+07:00", FormatTime(at, TimeFormatRFC3339))
+	assert.Equal(t, "2022-01-02T03:04:05.123456789+07:00", FormatTime(at, "RFC3339Nano"))
+	assert.Equal(t, "1641067445", FormatTime(at, TimeFormatEpoch))
+	assert.Equal(t, "1641067445123", FormatTime(at, TimeFormatEpochMillis))
+	assert.Equal(t, "02/01/2022 03:04", FormatTime(at, "02/01/2006 15:04"))
+
+	var entry = &Entry{Level: Info, Time: at, Message: "hi"}
+	assert.Equal(t, `{"level":"INFO","message":"hi","time":1641067445123}`, string((&JSONEncoder{TimeFormat: TimeFormatEpochMillis}).Encode(entry)))
+	assert.Equal(t, "1641067445 INFO hi", string((&TextEncoder{TimeFormat: TimeFormatEpoch}).Encode(entry)))
+}
+
+func TestTimeLocation(t *testing.T) {
+	assert.Equal(t, time.Local, New(&Config{Sync: true}).config.TimeLocation)
+
+	var buf bytes.Buffer
+	var sink = NewRingBuffer(1)
+	var location = time.FixedZone("EST", -5*3600)
+	var logger = New(&Config{
+		Sync:         true,
+		TimeLocation: location,
+		DateFormat:   TimeFormatRFC3339,
+		Console:      log.New(&buf, "", 0),
+		Sinks:        []Sink{sink},
+	})
+	defer logger.Close()
+
+	logger.Info("hello")
+
+	var entry = sink.Entries(nil)[0]
+	assert.Equal(t, location, entry.Time.Location())
+	assert.Contains(t, buf.String(), FormatTime(entry.Time, time.RFC3339))
+	assert.Contains(t, buf.String(), "-05:00")
+}
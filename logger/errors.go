@@ -0,0 +1,68 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+// ErrorKey field key used by Err
+const ErrorKey = "error"
+
+// ErrorDetail error message, type, unwrap chain and stack trace
+type ErrorDetail struct {
+	Message string        `json:"message"`
+	Type    string        `json:"type"`
+	Chain   []ErrorDetail `json:"chain,omitempty"`
+	Stack   string        `json:"stack,omitempty"`
+}
+
+type stackTracer interface {
+	StackTrace() pkgerrors.StackTrace
+}
+
+// Err error field, records the message, type, errors.Unwrap chain
+// and the stack trace of errors created by github.com/pkg/errors
+func Err(err error) Field {
+	if err == nil {
+		return Any(ErrorKey, nil)
+	}
+
+	return Any(ErrorKey, NewErrorDetail(err))
+}
+
+// NewErrorDetail builds the error detail
+func NewErrorDetail(err error) *ErrorDetail {
+	var detail = &ErrorDetail{
+		Message: err.Error(),
+		Type:    fmt.Sprintf("%T", err),
+	}
+
+	// The deepest stack trace points to where the error was created
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if e != err {
+			detail.Chain = append(detail.Chain, ErrorDetail{
+				Message: e.Error(),
+				Type:    fmt.Sprintf("%T", e),
+			})
+		}
+
+		if tracer, ok := e.(stackTracer); ok {
+			detail.Stack = strings.TrimPrefix(fmt.Sprintf("%+v", tracer.StackTrace()), "\n")
+		}
+	}
+
+	return detail
+}
+
+// String implements fmt.Stringer
+func (detail *ErrorDetail) String() string {
+	var parts = []string{fmt.Sprintf("%s (%s)", detail.Message, detail.Type)}
+	for _, cause := range detail.Chain {
+		parts = append(parts, fmt.Sprintf("%s (%s)", cause.Message, cause.Type))
+	}
+
+	return strings.Join(parts, " caused by: ")
+}
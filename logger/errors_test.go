@@ -0,0 +1,58 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErr(t *testing.T) {
+	var cause = pkgerrors.New("record not found")
+	var err = fmt.Errorf("find user: %w", pkgerrors.Wrap(cause, "query"))
+
+	var field = Err(err)
+	assert.Equal(t, ErrorKey, field.Key)
+
+	var detail = field.Value.(*ErrorDetail)
+	assert.Equal(t, "find user: query: record not found", detail.Message)
+	assert.Equal(t, "*fmt.wrapError", detail.Type)
+	assert.Equal(t, []ErrorDetail{
+		{Message: "query: record not found", Type: "*errors.withStack"},
+		{Message: "query: record not found", Type: "*errors.withMessage"},
+		{Message: "record not found", Type: "*errors.fundamental"},
+	}, detail.Chain)
+	assert.True(t, strings.HasPrefix(detail.Stack, "github.com/thaitanloi365/gocore/logger.TestErr\n"))
+	assert.Equal(t, "error=find user: query: record not found (*fmt.wrapError) caused by: query: record not found (*errors.withStack) caused by: query: record not found (*errors.withMessage) caused by: record not found (*errors.fundamental)", field.String())
+
+	assert.Equal(t, "error=EOF (*errors.errorString)", Err(io.EOF).String())
+	assert.Equal(t, "error=<nil>", Err(nil).String())
+}
+
+func TestErrOutput(t *testing.T) {
+	var writer = newCaptureWriter()
+	var logger = New(&Config{Writer: writer, Format: FormatJSON})
+
+	logger.Error("create order failed", Any("order_id", 1), Err(pkgerrors.New("boom")))
+
+	var entry map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(writer.next(t)), &entry))
+	assert.Equal(t, "ERROR", entry["level"])
+	assert.Equal(t, "create order failed", entry["message"])
+	assert.Equal(t, float64(1), entry["order_id"])
+	assert.Nil(t, entry["stack"])
+
+	var detail = entry["error"].(map[string]interface{})
+	assert.Equal(t, "boom", detail["message"])
+	assert.Equal(t, "*errors.fundamental", detail["type"])
+	assert.Contains(t, detail["stack"], "logger.TestErrOutput")
+
+	logger = New(&Config{Writer: writer})
+	logger.Error("failed", Err(pkgerrors.New("boom")))
+	assert.Regexp(t, `ERROR .+ failed error=boom \(\*errors.fundamental\) $`, writer.next(t))
+	assert.Contains(t, writer.next(t), "logger.TestErrOutput")
+}
@@ -0,0 +1,27 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Field key/value pair, printed as key=value
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// Any new field
+func Any(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// String implements fmt.Stringer
+func (f Field) String() string {
+	return fmt.Sprintf("%s=%v", f.Key, f.Value)
+}
+
+// MarshalJSON encodes the field as {"key": value}
+func (f Field) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{f.Key: f.Value})
+}
@@ -1,5 +1,19 @@
 package logger
 
+import (
+	"sync"
+)
+
+// RotationInterval time based rotation
+type RotationInterval string
+
+// All rotation intervals
+const (
+	RotateNone   RotationInterval = ""
+	RotateHourly RotationInterval = "hourly"
+	RotateDaily  RotationInterval = "daily"
+)
+
 // FileLogConfig file config
 type FileLogConfig struct {
 	Filename   string
@@ -8,6 +22,15 @@ type FileLogConfig struct {
 	MaxAge     int  //days
 	Compress   bool // disabled by default
 
+	// Rotation rotates the file every hour or day in addition to MaxSize
+	Rotation RotationInterval
+	// Format of the lines, defaults to FormatText
+	Format LogFormat
+	// TimeFormat of the entries, defaults to the logger date format
+	TimeFormat string
+	// OnRotate called with the path of every rotated file, after compression. MaxBackups
+	// and MaxAge only clean up files whose OnRotate returned
+	OnRotate func(path string)
 }
 
 // NewFileLog init new log config
@@ -44,3 +67,61 @@ func (f *FileLogConfig) WithMaxAge(days int) *FileLogConfig {
 	f.MaxAge = days
 	return f
 }
+
+// WithRotation overrides time based rotation
+func (f *FileLogConfig) WithRotation(rotation RotationInterval) *FileLogConfig {
+	f.Rotation = rotation
+	return f
+}
+
+// WithFormat overrides line format
+func (f *FileLogConfig) WithFormat(format LogFormat) *FileLogConfig {
+	f.Format = format
+	return f
+}
+
+// WithOnRotate overrides rotation hook
+func (f *FileLogConfig) WithOnRotate(onRotate func(path string)) *FileLogConfig {
+	f.OnRotate = onRotate
+	return f
+}
+
+// FileSink writes encoded entries to a rotating file
+type FileSink struct {
+	mutex   sync.Mutex
+	file    *RotatingFile
+	encoder Encoder
+}
+
+// NewFileSink new file sink, the file is opened on the first write
+func NewFileSink(config *FileLogConfig) *FileSink {
+	var timeFormat = config.TimeFormat
+	if timeFormat == "" {
+		timeFormat = defaultDateFormat
+	}
+
+	return &FileSink{
+		file:    NewRotatingFile(config),
+		encoder: NewEncoder(config.Format, timeFormat),
+	}
+}
+
+// File underlying rotating file
+func (s *FileSink) File() *RotatingFile {
+	return s.file
+}
+
+// Write implements Sink
+func (s *FileSink) Write(entry *Entry) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var line = append(s.encoder.Encode(entry), '\n')
+	_, err := s.file.Write(line)
+	return err
+}
+
+// Close implements Sink
+func (s *FileSink) Close() error {
+	return s.file.Close()
+}
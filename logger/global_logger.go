@@ -4,20 +4,51 @@ import (
 	"sync"
 )
 
-var once = sync.Once{}
-
-var globalLog *Logger
+var (
+	globalMutex sync.RWMutex
+	globalLog   *Logger
+)
 
 // Global global
 func Global() *Logger {
+	globalMutex.RLock()
+	var l = globalLog
+	globalMutex.RUnlock()
+	if l != nil {
+		return l
+	}
+
 	return setupLog()
 }
 
 func setupLog() *Logger {
+	globalMutex.Lock()
+	defer globalMutex.Unlock()
+
 	if globalLog == nil {
-		once.Do(func() {
-			globalLog = New(nil)
-		})
+		globalLog = New(nil)
 	}
 	return globalLog
 }
+
+// SetGlobal set the global logger
+func SetGlobal(l *Logger) {
+	ReplaceGlobal(l)
+}
+
+// ReplaceGlobal set the global logger, the returned func restores the previous one
+func ReplaceGlobal(l *Logger) func() {
+	globalMutex.Lock()
+	var previous = globalLog
+	globalLog = l
+	globalMutex.Unlock()
+
+	return func() {
+		ReplaceGlobal(previous)
+	}
+}
+
+// Named named logger of the global logger
+func Named(name string) *Logger {
+	return Global().Named(name)
+}
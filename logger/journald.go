@@ -0,0 +1,208 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultJournaldSocket native protocol socket of systemd-journald
+const DefaultJournaldSocket = "/run/systemd/journal/socket"
+
+// JournaldConfig journald sink config
+type JournaldConfig struct {
+	// Socket defaults to DefaultJournaldSocket
+	Socket string
+	// Identifier SYSLOG_IDENTIFIER, defaults to the executable name
+	Identifier string
+	// Facility SYSLOG_FACILITY, defaults to FacilityUser
+	Facility int
+}
+
+// JournaldSink writes entries with their fields to the native journal socket,
+// fields are upper cased, e.g. order_id is ORDER_ID, and query with journalctl ORDER_ID=1
+type JournaldSink struct {
+	mutex  sync.Mutex
+	config *JournaldConfig
+	conn   *net.UnixConn
+	addr   *net.UnixAddr
+}
+
+// NewJournaldSink new journald sink
+func NewJournaldSink(config *JournaldConfig) *JournaldSink {
+	var cfg = *config
+	if cfg.Socket == "" {
+		cfg.Socket = DefaultJournaldSocket
+	}
+	if cfg.Identifier == "" {
+		cfg.Identifier = filepath.Base(os.Args[0])
+	}
+	if cfg.Facility <= 0 {
+		cfg.Facility = FacilityUser
+	}
+
+	return &JournaldSink{
+		config: &cfg,
+		addr:   &net.UnixAddr{Name: cfg.Socket, Net: "unixgram"},
+	}
+}
+
+// Write implements Sink
+func (s *JournaldSink) Write(entry *Entry) error {
+	var data = s.Format(entry)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.conn == nil {
+		conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Net: "unixgram"})
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+	}
+
+	_, _, err := s.conn.WriteMsgUnix(data, nil, s.addr)
+	return err
+}
+
+// Close implements Sink
+func (s *JournaldSink) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.conn == nil {
+		return nil
+	}
+
+	var err = s.conn.Close()
+	s.conn = nil
+
+	return err
+}
+
+// Format datagram of the native journal protocol
+func (s *JournaldSink) Format(entry *Entry) []byte {
+	var buf bytes.Buffer
+
+	var message = syslogText(entry)
+	if entry.Stack != "" {
+		message = strings.TrimSuffix(message, "\n"+entry.Stack)
+	}
+
+	writeJournalField(&buf, "MESSAGE", message)
+	writeJournalField(&buf, "PRIORITY", strconv.Itoa(SyslogSeverity(entry.Level)))
+	writeJournalField(&buf, "SYSLOG_IDENTIFIER", s.config.Identifier)
+	writeJournalField(&buf, "SYSLOG_FACILITY", strconv.Itoa(s.config.Facility))
+	writeJournalField(&buf, "LEVEL", entry.Level.String())
+
+	if entry.Logger != "" {
+		writeJournalField(&buf, "LOGGER", entry.Logger)
+	}
+
+	if entry.Caller != "" {
+		var file, line = entry.Caller, ""
+		if index := strings.LastIndex(entry.Caller, ":"); index > 0 {
+			file, line = entry.Caller[:index], entry.Caller[index+1:]
+		}
+		writeJournalField(&buf, "CODE_FILE", file)
+		if line != "" {
+			writeJournalField(&buf, "CODE_LINE", line)
+		}
+	}
+
+	if entry.Stack != "" {
+		writeJournalField(&buf, "STACK", entry.Stack)
+	}
+
+	if info := entry.Request; info != nil {
+		for _, field := range [][2]string{
+			{"REQUEST_ID", info.ID},
+			{"USER_ID", info.UserID},
+			{"REF_ERROR_ID", info.RefErrorID},
+			{"HTTP_METHOD", info.Method},
+			{"HTTP_URI", info.URI},
+			{"TRACE_ID", info.TraceID},
+			{"SPAN_ID", info.SpanID},
+		} {
+			if field[1] != "" {
+				writeJournalField(&buf, field[0], field[1])
+			}
+		}
+		if info.Status > 0 {
+			writeJournalField(&buf, "HTTP_STATUS", strconv.Itoa(info.Status))
+		}
+	}
+
+	for _, field := range entry.Fields {
+		if key := journalKey(field.Key); key != "" {
+			writeJournalField(&buf, key, journalValue(field.Value))
+		}
+	}
+
+	return buf.Bytes()
+}
+
+// writeJournalField KEY=value, values with newlines are written as KEY\n<little endian uint64 size>value
+func writeJournalField(buf *bytes.Buffer, key, value string) {
+	buf.WriteString(key)
+	if !strings.Contains(value, "\n") {
+		buf.WriteByte('=')
+		buf.WriteString(value)
+		buf.WriteByte('\n')
+		return
+	}
+
+	var size [8]byte
+	binary.LittleEndian.PutUint64(size[:], uint64(len(value)))
+	buf.WriteByte('\n')
+	buf.Write(size[:])
+	buf.WriteString(value)
+	buf.WriteByte('\n')
+}
+
+// journalKey journal field name of key: upper case letters, digits and underscores,
+// not starting with an underscore or a digit, at most 64 characters
+func journalKey(key string) string {
+	var name = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, key)
+
+	name = strings.TrimLeft(name, "_0123456789")
+	if len(name) > 64 {
+		name = name[:64]
+	}
+
+	return name
+}
+
+func journalValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case fmt.Stringer:
+		return v.String()
+	case error:
+		return v.Error()
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return string(data)
+}
@@ -0,0 +1,76 @@
+package logger
+
+import (
+	"encoding/binary"
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func readJournalFields(data []byte) map[string]string {
+	var fields = map[string]string{}
+	for len(data) > 0 {
+		var end = strings.IndexByte(string(data), '\n')
+		var line = string(data[:end])
+		if index := strings.IndexByte(line, '='); index >= 0 {
+			fields[line[:index]] = line[index+1:]
+			data = data[end+1:]
+			continue
+		}
+
+		var size = binary.LittleEndian.Uint64(data[end+1 : end+9])
+		fields[line] = string(data[end+9 : end+9+int(size)])
+		data = data[end+9+int(size)+1:]
+	}
+
+	return fields
+}
+
+func TestJournaldSink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "journal")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var socket = filepath.Join(dir, "socket")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	var sink = NewJournaldSink(&JournaldConfig{Socket: socket, Identifier: "api"})
+	defer sink.Close()
+
+	var entry = testSyslogEntry()
+	entry.Fields = append(entry.Fields, Any("user.email", "a@b.c"), Any("9lives", true), Any("err", errors.New("declined")))
+	assert.NoError(t, sink.Write(entry))
+
+	var buf = make([]byte, 4096)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, err := conn.Read(buf)
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"MESSAGE":           "charge failed order_id=7 user.email=a@b.c 9lives=true err=declined",
+		"PRIORITY":          "3",
+		"SYSLOG_IDENTIFIER": "api",
+		"SYSLOG_FACILITY":   "1",
+		"LEVEL":             "ERROR",
+		"LOGGER":            "payments",
+		"CODE_FILE":         "payments/charge.go",
+		"CODE_LINE":         "42",
+		"STACK":             "goroutine 1\nmain.go:10",
+		"REQUEST_ID":        "req-1",
+		"USER_ID":           `a"b]`,
+		"HTTP_STATUS":       "500",
+		"ORDER_ID":          "7",
+		"USER_EMAIL":        "a@b.c",
+		"LIVES":             "true",
+		"ERR":               "declined",
+	}, readJournalFields(buf[:n]))
+}
@@ -0,0 +1,739 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/thaitanloi365/gocore/logger/notifier"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigSpec serializable logger config, read by LoadConfig from a yaml/json file and LOG_* env vars.
+// date_format and the time_format of the sinks are time layouts or rfc3339, rfc3339nano, epoch, epoch_millis
+type ConfigSpec struct {
+	Level          string   `json:"level" yaml:"level"`
+	Format         string   `json:"format" yaml:"format"`
+	Colorful       *bool    `json:"colorful" yaml:"colorful"`
+	ForceColor     bool     `json:"force_color" yaml:"force_color"`
+	ConsoleFormat  string   `json:"console_format" yaml:"console_format"`
+	TimeZone       string   `json:"time_zone" yaml:"time_zone"`
+	DateFormat     string   `json:"date_format" yaml:"date_format"`
+	Prefix         string   `json:"prefix" yaml:"prefix"`
+	BufferedSize   int      `json:"buffered_size" yaml:"buffered_size"`
+	OverflowPolicy string   `json:"overflow_policy" yaml:"overflow_policy"`
+	RedactFields   []string `json:"redact_fields" yaml:"redact_fields"`
+
+	File     *FileSpec             `json:"file" yaml:"file"`
+	Sinks    []*SinkSpec           `json:"sinks" yaml:"sinks"`
+	Notifier *NotifierSpec         `json:"notifier" yaml:"notifier"`
+	Loggers  map[string]*NamedSpec `json:"loggers" yaml:"loggers"`
+
+	// sources env vars of the fields read from the environment
+	sources map[string]string
+}
+
+// FileSpec rotating file of ConfigSpec
+type FileSpec struct {
+	Filename     string   `json:"filename" yaml:"filename"`
+	MaxSize      int      `json:"max_size" yaml:"max_size"`
+	MaxBackups   int      `json:"max_backups" yaml:"max_backups"`
+	MaxAge       int      `json:"max_age" yaml:"max_age"`
+	Compress     *bool    `json:"compress" yaml:"compress"`
+	Rotation     string   `json:"rotation" yaml:"rotation"`
+	Format       string   `json:"format" yaml:"format"`
+	TimeFormat   string   `json:"time_format" yaml:"time_format"`
+	ExceptLevels []string `json:"except_levels" yaml:"except_levels"`
+}
+
+// SinkSpec sink of ConfigSpec, type is stdout, stderr, file, ring_buffer, syslog or journald
+type SinkSpec struct {
+	Type string `json:"type" yaml:"type"`
+	// Size entries kept by a ring_buffer
+	Size int `json:"size" yaml:"size"`
+
+	// Network and Address of syslog, Address is the socket path of journald
+	Network string `json:"network" yaml:"network"`
+	Address string `json:"address" yaml:"address"`
+	// Facility of syslog and journald, e.g. user or local0
+	Facility string `json:"facility" yaml:"facility"`
+	// AppName syslog app name and journald identifier
+	AppName string `json:"app_name" yaml:"app_name"`
+
+	FileSpec `yaml:",inline"`
+}
+
+// NotifierSpec notifier of ConfigSpec, type is slack, teams, discord, telegram or webhook
+type NotifierSpec struct {
+	Type       string `json:"type" yaml:"type"`
+	WebhookURL string `json:"webhook_url" yaml:"webhook_url"`
+	ProxyURL   string `json:"proxy_url" yaml:"proxy_url"`
+
+	// Slack
+	Channel     string `json:"channel" yaml:"channel"`
+	Formatter   string `json:"formatter" yaml:"formatter"`
+	Service     string `json:"service" yaml:"service"`
+	Environment string `json:"environment" yaml:"environment"`
+	LogsURL     string `json:"logs_url" yaml:"logs_url"`
+
+	// Discord
+	Username string `json:"username" yaml:"username"`
+
+	// Telegram
+	BotToken string `json:"bot_token" yaml:"bot_token"`
+	ChatID   string `json:"chat_id" yaml:"chat_id"`
+
+	// Webhook
+	Template string            `json:"template" yaml:"template"`
+	Method   string            `json:"method" yaml:"method"`
+	Headers  map[string]string `json:"headers" yaml:"headers"`
+
+	Async *AsyncSpec `json:"async" yaml:"async"`
+}
+
+// AsyncSpec async notifier of NotifierSpec, zero values use notifier.DefaultAsyncConfig
+type AsyncSpec struct {
+	QueueSize    int     `json:"queue_size" yaml:"queue_size"`
+	RateLimit    float64 `json:"rate_limit" yaml:"rate_limit"`
+	Burst        int     `json:"burst" yaml:"burst"`
+	BatchWindow  string  `json:"batch_window" yaml:"batch_window"`
+	MaxBatchSize int     `json:"max_batch_size" yaml:"max_batch_size"`
+	Deduplicate  *bool   `json:"deduplicate" yaml:"deduplicate"`
+	MaxRetries   int     `json:"max_retries" yaml:"max_retries"`
+	RetryBackoff string  `json:"retry_backoff" yaml:"retry_backoff"`
+}
+
+// NamedSpec named logger of ConfigSpec
+type NamedSpec struct {
+	Level  string                 `json:"level" yaml:"level"`
+	Prefix string                 `json:"prefix" yaml:"prefix"`
+	Fields map[string]interface{} `json:"fields" yaml:"fields"`
+}
+
+// ConfigError invalid config field
+type ConfigError struct {
+	// Field path of the field, e.g. file.rotation or sinks[1].type
+	Field string
+	// Env var the value was read from
+	Env    string
+	Value  string
+	Reason string
+}
+
+func (e *ConfigError) Error() string {
+	var field = e.Field
+	if e.Env != "" {
+		field = fmt.Sprintf("%s (%s)", e.Field, e.Env)
+	}
+
+	return fmt.Sprintf("logger: invalid %s %q: %s", field, e.Value, e.Reason)
+}
+
+// ConfigErrors all invalid fields of a config
+type ConfigErrors []*ConfigError
+
+func (errs ConfigErrors) Error() string {
+	var messages = make([]string, 0, len(errs))
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// LoadConfig builds a config from the yaml or json file at path, then LOG_* env vars.
+// path is optional, env vars override the file values, invalid fields are returned as ConfigErrors
+func LoadConfig(path string) (*Config, error) {
+	var spec = &ConfigSpec{}
+	if path != "" {
+		var err error
+		spec, err = ReadConfigSpec(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if errs := spec.applyEnv(os.LookupEnv); len(errs) > 0 {
+		return nil, errs
+	}
+
+	return spec.Build()
+}
+
+// ReadConfigSpec reads a yaml or json file by its extension, unknown fields are errors
+func ReadConfigSpec(path string) (*ConfigSpec, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var spec = &ConfigSpec{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		var decoder = yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err = decoder.Decode(spec); err == io.EOF {
+			err = nil
+		}
+	case ".json":
+		var decoder = json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(spec)
+	default:
+		return nil, fmt.Errorf("logger: unsupported config file %s, use .yaml, .yml or .json", path)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("logger: parse %s: %w", path, err)
+	}
+
+	return spec, nil
+}
+
+// Build validates the spec and builds the config, sinks and notifier are created
+func (spec *ConfigSpec) Build() (*Config, error) {
+	var b = &configBuilder{spec: spec}
+	var config = &Config{
+		Level:         b.level("level", spec.Level),
+		Format:        b.format("format", spec.Format),
+		Colorful:      spec.Colorful == nil || *spec.Colorful,
+		ForceColor:    spec.ForceColor,
+		ConsoleFormat: b.consoleFormat("console_format", spec.ConsoleFormat),
+		DateFormat:    spec.DateFormat,
+		Prefix:        spec.Prefix,
+		BufferedSize:  spec.BufferedSize,
+	}
+
+	if spec.TimeZone != "" {
+		location, err := time.LoadLocation(spec.TimeZone)
+		if err != nil {
+			b.fail("time_zone", spec.TimeZone, "unknown time zone")
+		}
+		config.TimeLocation = location
+	}
+
+	b.positive("buffered_size", spec.BufferedSize)
+
+	config.OverflowPolicy = b.overflowPolicy("overflow_policy", spec.OverflowPolicy)
+
+	if len(spec.RedactFields) > 0 {
+		config.Redactor = NewRedactor(append(append([]string{}, DefaultRedactFields...), spec.RedactFields...))
+	}
+
+	if spec.File != nil {
+		config.File = b.file("file", spec.File)
+		config.WriteFileExceptLevels = b.levels("file.except_levels", spec.File.ExceptLevels)
+	}
+
+	for i, sinkSpec := range spec.Sinks {
+		if sink := b.sink(fmt.Sprintf("sinks[%d]", i), sinkSpec); sink != nil {
+			config.Sinks = append(config.Sinks, sink)
+		}
+	}
+
+	if spec.Notifier != nil {
+		config.Notifier = b.notifier("notifier", spec.Notifier)
+		if spec.Notifier.Async != nil {
+			config.NotifierAsync = b.async("notifier.async", spec.Notifier.Async)
+		}
+	}
+
+	var names = make([]string, 0, len(spec.Loggers))
+	for name := range spec.Loggers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		var namedSpec = spec.Loggers[name]
+		if namedSpec == nil {
+			continue
+		}
+		if config.Loggers == nil {
+			config.Loggers = map[string]*NamedConfig{}
+		}
+		config.Loggers[name] = &NamedConfig{
+			Level:  b.level("loggers."+name+".level", namedSpec.Level),
+			Prefix: namedSpec.Prefix,
+			Fields: namedSpec.Fields,
+		}
+	}
+
+	if len(b.errs) > 0 {
+		for _, sink := range config.Sinks {
+			sink.Close()
+		}
+		return nil, b.errs
+	}
+
+	return config, nil
+}
+
+// applyEnv overrides the spec with the LOG_* env vars
+func (spec *ConfigSpec) applyEnv(lookup func(string) (string, bool)) ConfigErrors {
+	var errs ConfigErrors
+	var set = func(env, field string, apply func(value string) error) {
+		value, ok := lookup(env)
+		if !ok {
+			return
+		}
+
+		if err := apply(strings.TrimSpace(value)); err != nil {
+			errs = append(errs, &ConfigError{Field: field, Env: env, Value: value, Reason: err.Error()})
+			return
+		}
+
+		if spec.sources == nil {
+			spec.sources = map[string]string{}
+		}
+		spec.sources[field] = env
+	}
+	var str = func(dst *string) func(string) error {
+		return func(value string) error {
+			*dst = value
+			return nil
+		}
+	}
+	var integer = func(dst *int) func(string) error {
+		return func(value string) error {
+			v, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("must be an integer")
+			}
+			*dst = v
+			return nil
+		}
+	}
+	var boolean = func(dst **bool) func(string) error {
+		return func(value string) error {
+			v, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("must be a boolean")
+			}
+			*dst = &v
+			return nil
+		}
+	}
+	var list = func(dst *[]string) func(string) error {
+		return func(value string) error {
+			*dst = splitList(value)
+			return nil
+		}
+	}
+
+	set("LOG_LEVEL", "level", str(&spec.Level))
+	set("LOG_FORMAT", "format", str(&spec.Format))
+	set("LOG_COLORFUL", "colorful", boolean(&spec.Colorful))
+	set("LOG_CONSOLE_FORMAT", "console_format", str(&spec.ConsoleFormat))
+	set("LOG_TIME_ZONE", "time_zone", str(&spec.TimeZone))
+	set("LOG_DATE_FORMAT", "date_format", str(&spec.DateFormat))
+	set("LOG_PREFIX", "prefix", str(&spec.Prefix))
+	set("LOG_BUFFERED_SIZE", "buffered_size", integer(&spec.BufferedSize))
+	set("LOG_OVERFLOW_POLICY", "overflow_policy", str(&spec.OverflowPolicy))
+	set("LOG_REDACT_FIELDS", "redact_fields", list(&spec.RedactFields))
+
+	var file = spec.File
+	if file == nil {
+		file = &FileSpec{}
+	}
+	set("LOG_FILE", "file.filename", str(&file.Filename))
+	set("LOG_FILE_MAX_SIZE", "file.max_size", integer(&file.MaxSize))
+	set("LOG_FILE_MAX_BACKUPS", "file.max_backups", integer(&file.MaxBackups))
+	set("LOG_FILE_MAX_AGE", "file.max_age", integer(&file.MaxAge))
+	set("LOG_FILE_COMPRESS", "file.compress", boolean(&file.Compress))
+	set("LOG_FILE_ROTATION", "file.rotation", str(&file.Rotation))
+	set("LOG_FILE_FORMAT", "file.format", str(&file.Format))
+	set("LOG_FILE_TIME_FORMAT", "file.time_format", str(&file.TimeFormat))
+	set("LOG_FILE_EXCEPT_LEVELS", "file.except_levels", list(&file.ExceptLevels))
+	if spec.File == nil && spec.fromEnv("file.") {
+		spec.File = file
+	}
+
+	// LOG_SINKS=stdout:json,stderr replaces the sinks, file sinks need a config file
+	set("LOG_SINKS", "sinks", func(value string) error {
+		spec.Sinks = nil
+		for _, item := range splitList(value) {
+			var parts = strings.SplitN(item, ":", 2)
+			var sink = &SinkSpec{Type: parts[0]}
+			if len(parts) == 2 {
+				sink.Format = parts[1]
+			}
+			spec.Sinks = append(spec.Sinks, sink)
+		}
+		return nil
+	})
+
+	var n = spec.Notifier
+	if n == nil {
+		n = &NotifierSpec{}
+	}
+	set("LOG_NOTIFIER", "notifier.type", str(&n.Type))
+	set("LOG_NOTIFIER_WEBHOOK_URL", "notifier.webhook_url", str(&n.WebhookURL))
+	set("LOG_NOTIFIER_PROXY_URL", "notifier.proxy_url", str(&n.ProxyURL))
+	set("LOG_NOTIFIER_CHANNEL", "notifier.channel", str(&n.Channel))
+	set("LOG_NOTIFIER_FORMATTER", "notifier.formatter", str(&n.Formatter))
+	set("LOG_NOTIFIER_SERVICE", "notifier.service", str(&n.Service))
+	set("LOG_NOTIFIER_ENVIRONMENT", "notifier.environment", str(&n.Environment))
+	set("LOG_NOTIFIER_LOGS_URL", "notifier.logs_url", str(&n.LogsURL))
+	set("LOG_NOTIFIER_USERNAME", "notifier.username", str(&n.Username))
+	set("LOG_NOTIFIER_BOT_TOKEN", "notifier.bot_token", str(&n.BotToken))
+	set("LOG_NOTIFIER_CHAT_ID", "notifier.chat_id", str(&n.ChatID))
+	set("LOG_NOTIFIER_TEMPLATE", "notifier.template", str(&n.Template))
+	set("LOG_NOTIFIER_METHOD", "notifier.method", str(&n.Method))
+	set("LOG_NOTIFIER_ASYNC", "notifier.async", func(value string) error {
+		async, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("must be a boolean")
+		}
+		if !async {
+			n.Async = nil
+		} else if n.Async == nil {
+			n.Async = &AsyncSpec{}
+		}
+		return nil
+	})
+	if spec.Notifier == nil && spec.fromEnv("notifier.") {
+		spec.Notifier = n
+	}
+
+	return errs
+}
+
+// fromEnv whether a field starting with prefix was read from the environment
+func (spec *ConfigSpec) fromEnv(prefix string) bool {
+	for field := range spec.sources {
+		if strings.HasPrefix(field, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type configBuilder struct {
+	spec *ConfigSpec
+	errs ConfigErrors
+}
+
+func (b *configBuilder) fail(field, value, reason string) {
+	b.errs = append(b.errs, &ConfigError{
+		Field:  field,
+		Env:    b.spec.sources[field],
+		Value:  value,
+		Reason: reason,
+	})
+}
+
+func (b *configBuilder) positive(field string, value int) {
+	if value < 0 {
+		b.fail(field, strconv.Itoa(value), "must not be negative")
+	}
+}
+
+func (b *configBuilder) level(field, value string) LogLevel {
+	if value == "" {
+		return 0
+	}
+
+	level, err := ParseLevel(value)
+	if err != nil {
+		b.fail(field, value, "must be debug, info, warn or error")
+	}
+
+	return level
+}
+
+func (b *configBuilder) levels(field string, values []string) []LogLevel {
+	var levels []LogLevel
+	for _, value := range values {
+		if level := b.level(field, value); level != 0 {
+			levels = append(levels, level)
+		}
+	}
+
+	return levels
+}
+
+func (b *configBuilder) format(field, value string) LogFormat {
+	switch LogFormat(strings.ToLower(value)) {
+	case "", FormatText:
+		return FormatText
+	case FormatJSON:
+		return FormatJSON
+	}
+
+	b.fail(field, value, "must be text or json")
+	return FormatText
+}
+
+func (b *configBuilder) consoleFormat(field, value string) ConsoleFormat {
+	switch ConsoleFormat(strings.ToLower(value)) {
+	case "", ConsolePretty:
+		return ConsolePretty
+	case ConsoleCompact:
+		return ConsoleCompact
+	}
+
+	b.fail(field, value, "must be pretty or compact")
+	return ConsolePretty
+}
+
+func (b *configBuilder) overflowPolicy(field, value string) OverflowPolicy {
+	switch strings.ToLower(value) {
+	case "", "block":
+		return OverflowBlock
+	case "drop_newest":
+		return OverflowDropNewest
+	case "drop_oldest":
+		return OverflowDropOldest
+	case "sample":
+		return OverflowSample
+	}
+
+	b.fail(field, value, "must be block, drop_newest, drop_oldest or sample")
+	return OverflowBlock
+}
+
+func (b *configBuilder) duration(field, value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		b.fail(field, value, "must be a positive duration like 5s")
+		return 0
+	}
+
+	return duration
+}
+
+func (b *configBuilder) file(field string, spec *FileSpec) *FileLogConfig {
+	if spec.Filename == "" {
+		b.fail(field+".filename", "", "is required")
+	}
+
+	var config = NewFileLog(spec.Filename)
+	b.positive(field+".max_size", spec.MaxSize)
+	b.positive(field+".max_backups", spec.MaxBackups)
+	b.positive(field+".max_age", spec.MaxAge)
+	if spec.MaxSize > 0 {
+		config.WithMaxSize(spec.MaxSize)
+	}
+	if spec.MaxBackups > 0 {
+		config.WithMaxBackups(spec.MaxBackups)
+	}
+	if spec.MaxAge > 0 {
+		config.WithMaxAge(spec.MaxAge)
+	}
+	if spec.Compress != nil {
+		config.WithCompress(*spec.Compress)
+	}
+
+	switch RotationInterval(strings.ToLower(spec.Rotation)) {
+	case RotateNone, RotateHourly, RotateDaily:
+		config.WithRotation(RotationInterval(strings.ToLower(spec.Rotation)))
+	default:
+		b.fail(field+".rotation", spec.Rotation, "must be hourly or daily")
+	}
+
+	config.WithFormat(b.format(field+".format", spec.Format))
+	config.TimeFormat = spec.TimeFormat
+
+	return config
+}
+
+func (b *configBuilder) sink(field string, spec *SinkSpec) Sink {
+	if spec == nil {
+		b.fail(field, "", "is empty")
+		return nil
+	}
+
+	var except = b.levels(field+".except_levels", spec.ExceptLevels)
+	var format = b.format(field+".format", spec.Format)
+
+	// Sinks without a time format use the logger date format
+	var timeFormat = spec.TimeFormat
+	if timeFormat == "" {
+		timeFormat = b.spec.DateFormat
+	}
+
+	var sink Sink
+	switch strings.ToLower(spec.Type) {
+	case "stdout":
+		sink = NewWriterSink(os.Stdout, format, timeFormat)
+	case "stderr":
+		sink = NewWriterSink(os.Stderr, format, timeFormat)
+	case "file":
+		var config = b.file(field, &spec.FileSpec)
+		config.TimeFormat = timeFormat
+		sink = NewFileSink(config)
+	case "ring_buffer":
+		b.positive(field+".size", spec.Size)
+		sink = NewRingBuffer(spec.Size)
+	case "syslog":
+		switch spec.Network {
+		case "", "udp", "tcp", "unix", "unixgram":
+		default:
+			b.fail(field+".network", spec.Network, "must be udp, tcp, unix or unixgram")
+		}
+		if spec.Address == "" {
+			b.fail(field+".address", "", "is required by the syslog sink")
+		}
+		sink = NewSyslogSink(&SyslogConfig{
+			Network:  spec.Network,
+			Address:  spec.Address,
+			Facility: b.facility(field+".facility", spec.Facility),
+			AppName:  spec.AppName,
+			Format:   format,
+		})
+	case "journald":
+		sink = NewJournaldSink(&JournaldConfig{
+			Socket:     spec.Address,
+			Identifier: spec.AppName,
+			Facility:   b.facility(field+".facility", spec.Facility),
+		})
+	default:
+		b.fail(field+".type", spec.Type, "must be stdout, stderr, file, ring_buffer, syslog or journald")
+		return nil
+	}
+
+	if len(except) > 0 {
+		return &levelSink{Sink: sink, except: except}
+	}
+
+	return sink
+}
+
+var facilities = map[string]int{
+	"user":   FacilityUser,
+	"daemon": FacilityDaemon,
+	"auth":   FacilityAuth,
+	"local0": FacilityLocal0,
+	"local1": FacilityLocal1,
+	"local2": FacilityLocal2,
+	"local3": FacilityLocal3,
+	"local4": FacilityLocal4,
+	"local5": FacilityLocal5,
+	"local6": FacilityLocal6,
+	"local7": FacilityLocal7,
+}
+
+func (b *configBuilder) facility(field, value string) int {
+	if value == "" {
+		return FacilityUser
+	}
+
+	facility, ok := facilities[strings.ToLower(value)]
+	if !ok {
+		b.fail(field, value, "must be user, daemon, auth or local0-7")
+	}
+
+	return facility
+}
+
+func (b *configBuilder) notifier(field string, spec *NotifierSpec) notifier.Notifier {
+	var require = func(name, value string) {
+		if value == "" {
+			b.fail(field+"."+name, "", fmt.Sprintf("is required by the %s notifier", spec.Type))
+		}
+	}
+
+	switch strings.ToLower(spec.Type) {
+	case "slack":
+		require("webhook_url", spec.WebhookURL)
+		var slack = notifier.New(spec.WebhookURL, spec.ProxyURL, spec.Channel)
+		switch strings.ToLower(spec.Formatter) {
+		case "", "code":
+		case "block_kit":
+			slack.WithFormatter(notifier.BlockKitFormatter{
+				Service:     spec.Service,
+				Environment: spec.Environment,
+				LogsURL:     spec.LogsURL,
+			})
+		default:
+			b.fail(field+".formatter", spec.Formatter, "must be code or block_kit")
+		}
+		return slack
+
+	case "teams":
+		require("webhook_url", spec.WebhookURL)
+		return notifier.NewTeams(spec.WebhookURL, spec.ProxyURL)
+
+	case "discord":
+		require("webhook_url", spec.WebhookURL)
+		return notifier.NewDiscord(spec.WebhookURL, spec.ProxyURL, spec.Username)
+
+	case "telegram":
+		require("bot_token", spec.BotToken)
+		require("chat_id", spec.ChatID)
+		return notifier.NewTelegram(spec.BotToken, spec.ChatID, spec.ProxyURL)
+
+	case "webhook":
+		require("webhook_url", spec.WebhookURL)
+		webhook, err := notifier.NewWebhook(spec.WebhookURL, spec.ProxyURL, spec.Template)
+		if err != nil {
+			b.fail(field+".template", spec.Template, err.Error())
+			return nil
+		}
+		if spec.Method != "" {
+			webhook.Method = strings.ToUpper(spec.Method)
+		}
+		for key, value := range spec.Headers {
+			webhook.WithHeader(key, value)
+		}
+		return webhook
+	}
+
+	b.fail(field+".type", spec.Type, "must be slack, teams, discord, telegram or webhook")
+	return nil
+}
+
+func (b *configBuilder) async(field string, spec *AsyncSpec) *notifier.AsyncConfig {
+	var config = notifier.DefaultAsyncConfig
+	if spec.QueueSize > 0 {
+		config.QueueSize = spec.QueueSize
+	}
+	if spec.RateLimit > 0 {
+		config.RateLimit = spec.RateLimit
+	}
+	if spec.Burst > 0 {
+		config.Burst = spec.Burst
+	}
+	if window := b.duration(field+".batch_window", spec.BatchWindow); window > 0 {
+		config.BatchWindow = window
+	}
+	if spec.MaxBatchSize > 0 {
+		config.MaxBatchSize = spec.MaxBatchSize
+	}
+	if spec.Deduplicate != nil {
+		config.Deduplicate = *spec.Deduplicate
+	}
+	if spec.MaxRetries > 0 {
+		config.MaxRetries = spec.MaxRetries
+	}
+	if backoff := b.duration(field+".retry_backoff", spec.RetryBackoff); backoff > 0 {
+		config.RetryBackoff = backoff
+	}
+
+	return &config
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
@@ -0,0 +1,197 @@
+package logger
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thaitanloi365/gocore/logger/notifier"
+)
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	var path = filepath.Join(t.TempDir(), name)
+	assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func envLookup(env map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}
+}
+
+func TestLoadConfigYAML(t *testing.T) {
+	var path = writeConfigFile(t, "log.yaml", `
+level: info
+format: json
+time_zone: UTC
+date_format: "2006-01-02T15:04:05Z07:00"
+overflow_policy: drop_oldest
+redact_fields: [pin]
+file:
+  filename: logs/app.log
+  max_size: 10
+  rotation: daily
+  compress: false
+  except_levels: [debug]
+sinks:
+  - type: stdout
+    format: json
+notifier:
+  type: slack
+  webhook_url: https://hooks.slack.com/services/x
+  formatter: block_kit
+  service: api
+  async:
+    batch_window: 2s
+loggers:
+  payments:
+    level: debug
+    fields:
+      service: payments
+`)
+
+	spec, err := ReadConfigSpec(path)
+	assert.NoError(t, err)
+
+	config, err := spec.Build()
+	assert.NoError(t, err)
+	assert.Equal(t, Info, config.Level)
+	assert.Equal(t, FormatJSON, config.Format)
+	assert.Equal(t, time.UTC, config.TimeLocation)
+	assert.Equal(t, OverflowDropOldest, config.OverflowPolicy)
+	assert.True(t, config.Redactor.IsSensitive("pin"))
+	assert.True(t, config.Redactor.IsSensitive("password"))
+
+	assert.Equal(t, "logs/app.log", config.File.Filename)
+	assert.Equal(t, 10, config.File.MaxSize)
+	assert.Equal(t, 3, config.File.MaxBackups)
+	assert.Equal(t, RotateDaily, config.File.Rotation)
+	assert.False(t, config.File.Compress)
+	assert.Equal(t, []LogLevel{Debug}, config.WriteFileExceptLevels)
+
+	assert.Len(t, config.Sinks, 1)
+	assert.IsType(t, &WriterSink{}, config.Sinks[0])
+
+	var slack = config.Notifier.(*notifier.SlackNotifier)
+	assert.Equal(t, "https://hooks.slack.com/services/x", slack.WebhookURL)
+	assert.Equal(t, notifier.BlockKitFormatter{Service: "api"}, slack.Formatter)
+	assert.Equal(t, 2*time.Second, config.NotifierAsync.BatchWindow)
+	assert.Equal(t, notifier.DefaultAsyncConfig.MaxRetries, config.NotifierAsync.MaxRetries)
+
+	assert.Equal(t, Debug, config.Loggers["payments"].Level)
+	assert.Equal(t, "payments", config.Loggers["payments"].Fields["service"])
+}
+
+func TestLoadConfigJSON(t *testing.T) {
+	var path = writeConfigFile(t, "log.json", `{"level": "warn", "sinks": [{"type": "file", "filename": "app.log", "format": "json"}]}`)
+
+	config, err := LoadConfig(path)
+	assert.NoError(t, err)
+	assert.Equal(t, Warn, config.Level)
+	assert.IsType(t, &FileSink{}, config.Sinks[0])
+	assert.Equal(t, "app.log", config.Sinks[0].(*FileSink).File().Filename())
+}
+
+func TestLoadConfigUnknownField(t *testing.T) {
+	var path = writeConfigFile(t, "log.yaml", "level: info\nrotate: daily\n")
+
+	_, err := LoadConfig(path)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "line 2: field rotate not found")
+}
+
+func TestLoadConfigMalformedYAML(t *testing.T) {
+	for _, content := range []string{
+		"0: [:!00 \xef",
+		"level: [info",
+		"level: info\n  format: json\n",
+	} {
+		var path = writeConfigFile(t, "log.yaml", content)
+		assert.NotPanics(t, func() {
+			_, err := LoadConfig(path)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestConfigSpecEnv(t *testing.T) {
+	var spec = &ConfigSpec{Level: "debug"}
+	var errs = spec.applyEnv(envLookup(map[string]string{
+		"LOG_LEVEL":                "error",
+		"LOG_FILE":                 "app.log",
+		"LOG_FILE_ROTATION":        "hourly",
+		"LOG_SINKS":                "stdout:json, stderr, ring_buffer",
+		"LOG_NOTIFIER":             "telegram",
+		"LOG_NOTIFIER_BOT_TOKEN":   "token",
+		"LOG_NOTIFIER_CHAT_ID":     "42",
+		"LOG_NOTIFIER_ASYNC":       "true",
+		"LOG_COLORFUL":             "false",
+		"LOG_REDACT_FIELDS":        "pin, otp",
+		"LOG_NOTIFIER_ENVIRONMENT": "staging",
+	}))
+	assert.Empty(t, errs)
+
+	config, err := spec.Build()
+	assert.NoError(t, err)
+	assert.Equal(t, Error, config.Level)
+	assert.False(t, config.Colorful)
+	assert.Equal(t, "app.log", config.File.Filename)
+	assert.Equal(t, RotateHourly, config.File.Rotation)
+	assert.Len(t, config.Sinks, 3)
+	assert.IsType(t, &RingBuffer{}, config.Sinks[2])
+	assert.IsType(t, &notifier.TelegramNotifier{}, config.Notifier)
+	assert.NotNil(t, config.NotifierAsync)
+	assert.True(t, config.Redactor.IsSensitive("otp"))
+}
+
+func TestConfigSpecErrors(t *testing.T) {
+	var spec = &ConfigSpec{
+		Level:    "verbose",
+		TimeZone: "Mars/Olympus",
+		File:     &FileSpec{MaxSize: -1},
+		Sinks:    []*SinkSpec{{Type: "stdout"}, {Type: "kafka"}, {Type: "syslog", Facility: "mail"}},
+		Notifier: &NotifierSpec{Type: "slack"},
+		Loggers:  map[string]*NamedSpec{"payments": {Level: "loud"}},
+	}
+	var errs = spec.applyEnv(envLookup(map[string]string{
+		"LOG_FILE_ROTATION": "weekly",
+	}))
+	assert.Empty(t, errs)
+
+	_, err := spec.Build()
+	assert.IsType(t, ConfigErrors{}, err)
+
+	var fields = []string{}
+	for _, e := range err.(ConfigErrors) {
+		fields = append(fields, e.Field)
+	}
+	assert.Equal(t, []string{
+		"level",
+		"time_zone",
+		"file.filename",
+		"file.max_size",
+		"file.rotation",
+		"sinks[1].type",
+		"sinks[2].address",
+		"sinks[2].facility",
+		"notifier.webhook_url",
+		"loggers.payments.level",
+	}, fields)
+	assert.Contains(t, err.Error(), `logger: invalid file.rotation (LOG_FILE_ROTATION) "weekly": must be hourly or daily`)
+
+	errs = (&ConfigSpec{}).applyEnv(envLookup(map[string]string{"LOG_BUFFERED_SIZE": "lots"}))
+	assert.EqualError(t, errs, `logger: invalid buffered_size (LOG_BUFFERED_SIZE) "lots": must be an integer`)
+}
+
+func TestParseLevel(t *testing.T) {
+	level, err := ParseLevel("WARNING")
+	assert.NoError(t, err)
+	assert.Equal(t, Warn, level)
+
+	_, err = ParseLevel("trace")
+	assert.Error(t, err)
+}
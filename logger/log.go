@@ -2,9 +2,12 @@ package logger
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const RefErrorIDKey = "__ref_error_id"
@@ -25,16 +28,29 @@ type requestInfo struct {
 	uri        string
 	userID     string
 	refErrorID string
+	traceID    string
+	spanID     string
 }
 type logTask struct {
 	logger      *Logger
 	logLevel    LogLevel
-	time        string
+	at          time.Time
 	format      string
 	values      []interface{}
 	caller      string
+	stack       string
 	valueType   valueType
 	requestInfo *requestInfo
+	entry       *Entry
+	// buffer of the request the task was logged with, see RequestBuffer
+	buffer *requestBuffer
+	// flushed marks a Flush task, closed once the entries queued before it are written
+	flushed chan struct{}
+
+	// name, prefix and fields of the logger the task was logged with
+	name         string
+	prefix       string
+	loggerFields []Field
 }
 
 func (task *logTask) withRequestInfo(requestInfo *requestInfo) *logTask {
@@ -42,12 +58,22 @@ func (task *logTask) withRequestInfo(requestInfo *requestInfo) *logTask {
 	return task
 }
 
+func (task *logTask) withStack(stack string) *logTask {
+	task.stack = stack
+	return task
+}
+
 func (task *logTask) formatRequestInfo() string {
 	if task.requestInfo == nil {
 		return ""
 	}
 	var info = task.requestInfo
 
+	var parts = []string{}
+	if info.reqID != "" {
+		parts = append(parts, info.reqID)
+	}
+
 	var extras = []string{}
 	if info.userID != "" {
 		extras = append(extras, info.userID)
@@ -58,10 +84,24 @@ func (task *logTask) formatRequestInfo() string {
 	}
 
 	if len(extras) > 0 {
-		return fmt.Sprintf("%s [%v] %d %s %s", info.reqID, strings.Join(extras, "::"), info.status, info.method, info.uri)
+		parts = append(parts, fmt.Sprintf("[%v]", strings.Join(extras, "::")))
+	}
+
+	parts = append(parts, strconv.Itoa(info.status))
+
+	if info.method != "" {
+		parts = append(parts, info.method, info.uri)
+	}
+
+	if info.traceID != "" {
+		parts = append(parts, "trace="+info.traceID)
 	}
 
-	return fmt.Sprintf("%s %d %s %s", info.reqID, info.status, info.method, info.uri)
+	if info.spanID != "" {
+		parts = append(parts, "span="+info.spanID)
+	}
+
+	return strings.Join(parts, " ")
 }
 
 func (task *logTask) withEchoContext(c echo.Context) *logTask {
@@ -92,5 +132,26 @@ func (task *logTask) withEchoContext(c echo.Context) *logTask {
 		userID:     userID,
 		uri:        req.RequestURI,
 	}
-	return task.withRequestInfo(reqInfo)
+
+	if info := FromContext(req.Context()); info != nil {
+		reqInfo.traceID = info.TraceID
+		reqInfo.spanID = info.SpanID
+		if reqInfo.userID == "" {
+			reqInfo.userID = info.UserID
+		}
+		if reqInfo.refErrorID == "" {
+			reqInfo.refErrorID = info.RefErrorID
+		}
+	}
+
+	if spanContext := trace.SpanContextFromContext(req.Context()); spanContext.IsValid() {
+		reqInfo.traceID = spanContext.TraceID().String()
+		reqInfo.spanID = spanContext.SpanID().String()
+	}
+
+	task.withRequestInfo(reqInfo)
+	task.recordSpanEvent(req.Context())
+	task.buffer = requestBufferFromContext(req.Context())
+
+	return task
 }
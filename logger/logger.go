@@ -6,8 +6,6 @@ import (
 	"io/ioutil"
 	"log"
 	"os"
-	"runtime"
-	"strconv"
 	"sync"
 	"time"
 
@@ -15,15 +13,39 @@ import (
 	"github.com/thaitanloi365/gocore/logger/notifier"
 )
 
+const defaultDateFormat = "2006-01-02 15:04:05 Z07:00"
+
 // Logger instance
 type Logger struct {
+	// dropped and overflowed are updated atomically, keep them 64-bit aligned
+	dropped    uint64
+	overflowed uint64
+
 	context    context.Context
 	cancelFunc context.CancelFunc
 	config     *Config
 
-	mutex sync.RWMutex
+	// root is the logger owning the queue and sinks, nil for the root itself
+	root       *Logger
+	callerSkip int
+
+	// name, prefix, level and fields of the named and derived loggers
+	name   string
+	prefix string
+	level  LogLevel
+	fields []Field
+
+	// named loggers registry of the root
+	namedMutex sync.Mutex
+	named      map[string]*Logger
+
+	mutex     sync.RWMutex
+	closeOnce sync.Once
+	syncMutex sync.Mutex
 
-	queue chan *logTask
+	queue   chan *logTask
+	stopped chan struct{}
+	cleaned chan struct{}
 
 	writer     Writer
 	fileWriter Writer
@@ -40,39 +62,95 @@ type Logger struct {
 	errStr      string
 	errColorStr string
 
-	notifier *notifier.SlackNotifier
+	// compact writes single line console entries
+	compact bool
+
+	notifier notifier.Notifier
+	redactor *Redactor
+	samplers map[LogLevel]*sampler
+	sinks    []Sink
 }
 
 // Config log config
 type Config struct {
 	BufferedSize int
 	Colorful     bool
+	// TimeLocation of the timestamps, defaults to time.Local
 	TimeLocation *time.Location
-	DateFormat   string
-	Prefix       string
+	// DateFormat time layout or one of the TimeFormat* names, e.g. TimeFormatEpochMillis
+	DateFormat string
+	// Prefix written before every message
+	Prefix string
+	// Level minimum level, all levels are logged when zero
+	Level LogLevel
+	// Loggers configs of the named loggers by name
+	Loggers map[string]*NamedConfig
 
 	Writer                Writer
 	WriteFileExceptLevels []LogLevel
-
-	Notifier *notifier.SlackNotifier
+	// Format of the lines written to Writer, defaults to FormatText
+	Format LogFormat
+
+	// File writes to a rotating file, levels in WriteFileExceptLevels are skipped
+	File *FileLogConfig
+	// Sinks receive every entry
+	Sinks []Sink
+
+	// OverflowPolicy decides what happens when the queue is full, blocks by default
+	OverflowPolicy OverflowPolicy
+	// OverflowSampleRate keeps 1 of every N entries while the queue is full, used by OverflowSample
+	OverflowSampleRate int
+	// DroppedReportInterval how often the dropped entries counter is reported, defaults to 1 minute
+	DroppedReportInterval time.Duration
+
+	// Notifier is called synchronously for every notified entry, an error loop can flood
+	// the channel and hit the webhook rate limit. Set NotifierAsync in production
+	Notifier notifier.Notifier
+	// NotifierAsync sends notifications in background with rate limiting, batching and retries,
+	// e.g. &notifier.DefaultAsyncConfig
+	NotifierAsync *notifier.AsyncConfig
+
+	// Redactor masks sensitive values, defaults to DefaultRedactFields without free text patterns
+	Redactor *Redactor
+
+	// Sampling limits identical messages per level, levels without config are not sampled
+	Sampling map[LogLevel]*SamplingConfig
+
+	// CallerSkip skips extra stack frames when resolving the caller, for wrapper helpers
+	CallerSkip int
+
+	// Console receives the colored output, defaults to stdout
+	Console Writer
+	// ConsoleFormat defaults to ConsolePretty
+	ConsoleFormat ConsoleFormat
+	// Theme colors of the levels, DefaultTheme by default
+	Theme Theme
+	// ForceColor writes colors even when Console isn't a terminal, e.g. for docker -t
+	ForceColor bool
+	// Sync processes entries in the calling goroutine instead of the queue, for tests
+	Sync bool
+
+	// SpanEvents records Error entries logged with a context as events of its recording span
+	SpanEvents bool
 }
 
 // New new writter
 func New(config *Config) *Logger {
 	var bufferedSize = 10
-	var dateFormat = "2006-01-02 15:04:05 Z07:00"
-	timeLocation, _ := time.LoadLocation("Asia/Ho_Chi_Minh")
-
-	var defaultConfig = config
-	if defaultConfig == nil {
-		defaultConfig = &Config{
-			Prefix:       "",
-			BufferedSize: bufferedSize,
-			DateFormat:   dateFormat,
-			TimeLocation: timeLocation,
-			Colorful:     true,
-			Notifier:     nil,
-		}
+	var dateFormat = defaultDateFormat
+
+	var defaultConfig = &Config{
+		Prefix:       "",
+		BufferedSize: bufferedSize,
+		DateFormat:   dateFormat,
+		TimeLocation: time.Local,
+		Colorful:     true,
+		Notifier:     nil,
+	}
+	if config != nil {
+		// Defaults are filled in a copy, the config of the caller may be shared
+		var copied = *config
+		defaultConfig = &copied
 	}
 
 	if defaultConfig.BufferedSize == 0 {
@@ -84,25 +162,46 @@ func New(config *Config) *Logger {
 	}
 
 	if defaultConfig.TimeLocation == nil {
-		defaultConfig.TimeLocation = timeLocation
+		defaultConfig.TimeLocation = time.Local
+	}
+
+	if defaultConfig.OverflowSampleRate <= 0 {
+		defaultConfig.OverflowSampleRate = defaultOverflowSampleRate
+	}
+
+	if defaultConfig.DroppedReportInterval <= 0 {
+		defaultConfig.DroppedReportInterval = defaultDroppedReportInterval
 	}
 
-	var writer = log.New(os.Stdout, "\r\n", 0)
+	var writer Writer = log.New(os.Stdout, "\r\n", 0)
+	if defaultConfig.Console != nil {
+		writer = defaultConfig.Console
+	}
 	var fileWriter Writer = log.New(ioutil.Discard, "", 0)
 
 	if defaultConfig.Writer != nil {
 		fileWriter = defaultConfig.Writer
 	}
 
+	var colorful = defaultConfig.colorEnabled(writer)
+	var compact = defaultConfig.ConsoleFormat == ConsoleCompact
+	var consoleStr = func(level LogLevel) string {
+		var paint = defaultConfig.Theme.painter(level, colorful)
+		if compact {
+			return "%s " + paint(level.String()) + " %s "
+		}
+		return "%s " + paint(level.String()+" %s\n")
+	}
+
 	var (
 		debugStr      = "%s DEBUG %s "
 		infoStr       = "%s INFO %s "
 		warnStr       = "%s WARN %s "
 		errStr        = "%s ERROR %s "
-		debugColorStr = "%s " + Green("DEBUG %s\n")
-		infoColorStr  = "%s " + Blue("INFO %s\n")
-		warnColorStr  = "%s " + Yellow("WARN %s\n")
-		errColorStr   = "%s " + Red("ERROR %s\n")
+		debugColorStr = consoleStr(Debug)
+		infoColorStr  = consoleStr(Info)
+		warnColorStr  = consoleStr(Warn)
+		errColorStr   = consoleStr(Error)
 	)
 
 	ctx, cancelFunc := context.WithCancel(context.Background())
@@ -114,6 +213,8 @@ func New(config *Config) *Logger {
 		context:       ctx,
 		cancelFunc:    cancelFunc,
 		queue:         make(chan *logTask, defaultConfig.BufferedSize),
+		stopped:       make(chan struct{}),
+		cleaned:       make(chan struct{}),
 		debugStr:      debugStr,
 		debugColorStr: debugColorStr,
 		infoStr:       infoStr,
@@ -122,7 +223,42 @@ func New(config *Config) *Logger {
 		warnColorStr:  warnColorStr,
 		errStr:        errStr,
 		errColorStr:   errColorStr,
-		notifier:      defaultConfig.Notifier,
+		compact:       compact,
+		callerSkip:    defaultConfig.CallerSkip,
+		prefix:        defaultConfig.Prefix,
+		level:         defaultConfig.Level,
+	}
+
+	logger.redactor = defaultConfig.Redactor
+	if logger.redactor == nil {
+		logger.redactor = NewRedactor(DefaultRedactFields)
+	}
+
+	if defaultConfig.File != nil {
+		var file = *defaultConfig.File
+		if file.TimeFormat == "" {
+			file.TimeFormat = defaultConfig.DateFormat
+		}
+		defaultConfig.File = &file
+		logger.sinks = append(logger.sinks, &levelSink{
+			Sink:   NewFileSink(defaultConfig.File),
+			except: defaultConfig.WriteFileExceptLevels,
+		})
+	}
+	logger.sinks = append(logger.sinks, defaultConfig.Sinks...)
+
+	logger.samplers = map[LogLevel]*sampler{}
+	for level, sampling := range defaultConfig.Sampling {
+		if sampling != nil {
+			logger.samplers[level] = newSampler(*sampling)
+		}
+	}
+
+	if defaultConfig.Notifier != nil {
+		logger.notifier = defaultConfig.Notifier
+		if defaultConfig.NotifierAsync != nil {
+			logger.notifier = notifier.NewAsync(defaultConfig.Notifier, defaultConfig.NotifierAsync)
+		}
 	}
 
 	logger.run()
@@ -132,238 +268,479 @@ func New(config *Config) *Logger {
 
 // Printf debug
 func (l *Logger) Printf(format string, values ...interface{}) {
-	l.queue <- l.buildlog(Debug, "", valueTypeCustom, format, values...)
+	l.enqueue(l.buildlog(Debug, "", valueTypeCustom, format, values...))
 }
 
 // Debug debug
 func (l *Logger) Debug(values ...interface{}) {
-	l.queue <- l.buildlog(Debug, l.fileWithLineNum(), valueTypeInterface, "", values...)
+	l.enqueue(l.buildlog(Debug, l.fileWithLineNum(), valueTypeInterface, "", values...))
 }
 
 // DebugWithEchoContext wrap http
 func (l *Logger) DebugWithEchoContext(c echo.Context, values ...interface{}) {
-	l.queue <- l.buildlog(Debug, l.fileWithLineNum(), valueTypeInterface, "", values...).withEchoContext(c)
+	l.enqueue(l.buildlog(Debug, l.fileWithLineNum(), valueTypeInterface, "", values...).withEchoContext(c))
 }
 
 // Debugf debug with format
 func (l *Logger) Debugf(format string, values ...interface{}) {
-	l.queue <- l.buildlog(Debug, l.fileWithLineNum(), valueTypeInterface, format, values...)
+	l.enqueue(l.buildlog(Debug, l.fileWithLineNum(), valueTypeInterface, format, values...))
 }
 
 // DebugfWithEchoContext debug with format
 func (l *Logger) DebugfWithEchoContext(c echo.Context, format string, values ...interface{}) {
-	l.queue <- l.buildlog(Debug, l.fileWithLineNum(), valueTypeInterface, format, values...).withEchoContext(c)
+	l.enqueue(l.buildlog(Debug, l.fileWithLineNum(), valueTypeInterface, format, values...).withEchoContext(c))
 }
 
 // DebugJSON print pretty json
 func (l *Logger) DebugJSON(values ...interface{}) {
-	l.queue <- l.buildlog(Debug, l.fileWithLineNum(), valueTypeJSON, "", values...)
+	l.enqueue(l.buildlog(Debug, l.fileWithLineNum(), valueTypeJSON, "", values...))
 }
 
 // DebugJSONWithEchoContext print pretty json
 func (l *Logger) DebugJSONWithEchoContext(c echo.Context, values ...interface{}) {
-	l.queue <- l.buildlog(Debug, l.fileWithLineNum(), valueTypeJSON, "", values...).withEchoContext(c)
+	l.enqueue(l.buildlog(Debug, l.fileWithLineNum(), valueTypeJSON, "", values...).withEchoContext(c))
+}
+
+// DebugWithContext debug with request info from ctx
+func (l *Logger) DebugWithContext(ctx context.Context, values ...interface{}) {
+	l.enqueue(l.buildlog(Debug, l.fileWithLineNum(), valueTypeInterface, "", values...).withContext(ctx))
+}
+
+// DebugfWithContext debug with format and request info from ctx
+func (l *Logger) DebugfWithContext(ctx context.Context, format string, values ...interface{}) {
+	l.enqueue(l.buildlog(Debug, l.fileWithLineNum(), valueTypeInterface, format, values...).withContext(ctx))
+}
+
+// DebugJSONWithContext print pretty json with request info from ctx
+func (l *Logger) DebugJSONWithContext(ctx context.Context, values ...interface{}) {
+	l.enqueue(l.buildlog(Debug, l.fileWithLineNum(), valueTypeJSON, "", values...).withContext(ctx))
 }
 
 // Info info
 func (l *Logger) Info(values ...interface{}) {
-	l.queue <- l.buildlog(Info, l.fileWithLineNum(), valueTypeInterface, "", values...)
+	l.enqueue(l.buildlog(Info, l.fileWithLineNum(), valueTypeInterface, "", values...))
 }
 
 // InfofWithEchoContext info with format
 func (l *Logger) InfofWithEchoContext(c echo.Context, format string, values ...interface{}) {
-	l.queue <- l.buildlog(Info, l.fileWithLineNum(), valueTypeInterface, format, values...).withEchoContext(c)
+	l.enqueue(l.buildlog(Info, l.fileWithLineNum(), valueTypeInterface, format, values...).withEchoContext(c))
 }
 
 // Infof info with format
 func (l *Logger) Infof(format string, values ...interface{}) {
-	l.queue <- l.buildlog(Info, l.fileWithLineNum(), valueTypeInterface, format, values...)
+	l.enqueue(l.buildlog(Info, l.fileWithLineNum(), valueTypeInterface, format, values...))
 }
 
 // InfoWithEchoContext info with format
 func (l *Logger) InfoWithEchoContext(c echo.Context, format string, values ...interface{}) {
-	l.queue <- l.buildlog(Info, l.fileWithLineNum(), valueTypeInterface, format, values...).withEchoContext(c)
+	l.enqueue(l.buildlog(Info, l.fileWithLineNum(), valueTypeInterface, format, values...).withEchoContext(c))
 }
 
 // InfoJSON print pretty json
 func (l *Logger) InfoJSON(values ...interface{}) {
-	l.queue <- l.buildlog(Info, l.fileWithLineNum(), valueTypeJSON, "", values...)
+	l.enqueue(l.buildlog(Info, l.fileWithLineNum(), valueTypeJSON, "", values...))
 }
 
 // InfoJSONWithEchoContext print pretty json
 func (l *Logger) InfoJSONWithEchoContext(c echo.Context, values ...interface{}) {
-	l.queue <- l.buildlog(Info, l.fileWithLineNum(), valueTypeJSON, "", values...).withEchoContext(c)
+	l.enqueue(l.buildlog(Info, l.fileWithLineNum(), valueTypeJSON, "", values...).withEchoContext(c))
+}
+
+// InfoWithContext info with request info from ctx
+func (l *Logger) InfoWithContext(ctx context.Context, values ...interface{}) {
+	l.enqueue(l.buildlog(Info, l.fileWithLineNum(), valueTypeInterface, "", values...).withContext(ctx))
+}
+
+// InfofWithContext info with format and request info from ctx
+func (l *Logger) InfofWithContext(ctx context.Context, format string, values ...interface{}) {
+	l.enqueue(l.buildlog(Info, l.fileWithLineNum(), valueTypeInterface, format, values...).withContext(ctx))
+}
+
+// InfoJSONWithContext print pretty json with request info from ctx
+func (l *Logger) InfoJSONWithContext(ctx context.Context, values ...interface{}) {
+	l.enqueue(l.buildlog(Info, l.fileWithLineNum(), valueTypeJSON, "", values...).withContext(ctx))
 }
 
 // Warn warn
 func (l *Logger) Warn(values ...interface{}) {
-	l.queue <- l.buildlog(Warn, l.fileWithLineNum(), valueTypeInterface, "", values...)
+	l.enqueue(l.buildlog(Warn, l.fileWithLineNum(), valueTypeInterface, "", values...))
 }
 
 // Warn warn
 func (l *Logger) WarnWithEchoContext(c echo.Context, values ...interface{}) {
-	l.queue <- l.buildlog(Warn, l.fileWithLineNum(), valueTypeInterface, "", values...).withEchoContext(c)
+	l.enqueue(l.buildlog(Warn, l.fileWithLineNum(), valueTypeInterface, "", values...).withEchoContext(c))
 }
 
 // Warnf info with format
 func (l *Logger) Warnf(format string, values ...interface{}) {
-	l.queue <- l.buildlog(Warn, l.fileWithLineNum(), valueTypeInterface, format, values...)
+	l.enqueue(l.buildlog(Warn, l.fileWithLineNum(), valueTypeInterface, format, values...))
 }
 
 // WarnfWithEchoContext info with format
 func (l *Logger) WarnfWithEchoContext(c echo.Context, format string, values ...interface{}) {
-	l.queue <- l.buildlog(Warn, l.fileWithLineNum(), valueTypeInterface, format, values...).withEchoContext(c)
+	l.enqueue(l.buildlog(Warn, l.fileWithLineNum(), valueTypeInterface, format, values...).withEchoContext(c))
 }
 
 // WarnJSON print pretty json
 func (l *Logger) WarnJSON(values ...interface{}) {
-	l.queue <- l.buildlog(Warn, l.fileWithLineNum(), valueTypeJSON, "", values...)
+	l.enqueue(l.buildlog(Warn, l.fileWithLineNum(), valueTypeJSON, "", values...))
 }
 
 // WarnJSONWithEchoContext print pretty json
 func (l *Logger) WarnJSONWithEchoContext(c echo.Context, values ...interface{}) {
-	l.queue <- l.buildlog(Warn, l.fileWithLineNum(), valueTypeJSON, "", values...).withEchoContext(c)
+	l.enqueue(l.buildlog(Warn, l.fileWithLineNum(), valueTypeJSON, "", values...).withEchoContext(c))
+}
+
+// WarnWithContext warn with request info from ctx
+func (l *Logger) WarnWithContext(ctx context.Context, values ...interface{}) {
+	l.enqueue(l.buildlog(Warn, l.fileWithLineNum(), valueTypeInterface, "", values...).withContext(ctx))
+}
+
+// WarnfWithContext warn with format and request info from ctx
+func (l *Logger) WarnfWithContext(ctx context.Context, format string, values ...interface{}) {
+	l.enqueue(l.buildlog(Warn, l.fileWithLineNum(), valueTypeInterface, format, values...).withContext(ctx))
+}
+
+// WarnJSONWithContext print pretty json with request info from ctx
+func (l *Logger) WarnJSONWithContext(ctx context.Context, values ...interface{}) {
+	l.enqueue(l.buildlog(Warn, l.fileWithLineNum(), valueTypeJSON, "", values...).withContext(ctx))
 }
 
 // Error error
 func (l *Logger) Error(values ...interface{}) {
-	l.queue <- l.buildlog(Error, l.fileWithLineNum(), valueTypeInterface, "", values...)
+	l.enqueue(l.buildlog(Error, l.fileWithLineNum(), valueTypeInterface, "", values...))
 }
 
 // ErrorWithEchoContext error
 func (l *Logger) ErrorWithEchoContext(c echo.Context, values ...interface{}) {
-	l.queue <- l.buildlog(Error, l.fileWithLineNum(), valueTypeInterface, "", values...).withEchoContext(c)
+	l.enqueue(l.buildlog(Error, l.fileWithLineNum(), valueTypeInterface, "", values...).withEchoContext(c))
 }
 
 // Errorf error with format
 func (l *Logger) Errorf(format string, values ...interface{}) {
-	l.queue <- l.buildlog(Error, l.fileWithLineNum(), valueTypeInterface, format, values...)
+	l.enqueue(l.buildlog(Error, l.fileWithLineNum(), valueTypeInterface, format, values...))
 }
 
 // ErrorfWithEchoContext error with format
 func (l *Logger) ErrorfWithEchoContext(c echo.Context, format string, values ...interface{}) {
-	l.queue <- l.buildlog(Error, l.fileWithLineNum(), valueTypeInterface, format, values...).withEchoContext(c)
+	l.enqueue(l.buildlog(Error, l.fileWithLineNum(), valueTypeInterface, format, values...).withEchoContext(c))
 }
 
 // ErrorJSON print pretty json
 func (l *Logger) ErrorJSON(values ...interface{}) {
-	l.queue <- l.buildlog(Error, l.fileWithLineNum(), valueTypeJSON, "", values...)
+	l.enqueue(l.buildlog(Error, l.fileWithLineNum(), valueTypeJSON, "", values...))
 }
 
 // ErrorJSONWithEchoContext print pretty json
 func (l *Logger) ErrorJSONWithEchoContext(c echo.Context, values ...interface{}) {
-	l.queue <- l.buildlog(Error, l.fileWithLineNum(), valueTypeJSON, "", values...).withEchoContext(c)
+	l.enqueue(l.buildlog(Error, l.fileWithLineNum(), valueTypeJSON, "", values...).withEchoContext(c))
+}
+
+// ErrorWithContext error with request info from ctx
+func (l *Logger) ErrorWithContext(ctx context.Context, values ...interface{}) {
+	l.enqueue(l.buildlog(Error, l.fileWithLineNum(), valueTypeInterface, "", values...).withContext(ctx))
+}
+
+// ErrorfWithContext error with format and request info from ctx
+func (l *Logger) ErrorfWithContext(ctx context.Context, format string, values ...interface{}) {
+	l.enqueue(l.buildlog(Error, l.fileWithLineNum(), valueTypeInterface, format, values...).withContext(ctx))
+}
+
+// ErrorJSONWithContext print pretty json with request info from ctx
+func (l *Logger) ErrorJSONWithContext(ctx context.Context, values ...interface{}) {
+	l.enqueue(l.buildlog(Error, l.fileWithLineNum(), valueTypeJSON, "", values...).withContext(ctx))
 }
 
 func (l *Logger) run() {
 	go l.cleanup()
+	go l.reportDropped()
 
 	go func(ctx context.Context, queue chan *logTask) {
+		defer close(l.stopped)
+
 		for {
 			select {
 			case <-ctx.Done():
 				return
 
-			case data := <-queue:
-				var format = l.infoStr
-				var formatColor = l.infoColorStr
-				var extraFormat = data.format
-				var extraPrettyFormat = data.format
-				switch data.logLevel {
-				case Debug:
-					format = l.debugStr
-					formatColor = l.debugColorStr
-				case Error:
-					format = l.errStr
-					formatColor = l.errColorStr
-				case Warn:
-					format = l.warnStr
-					formatColor = l.warnColorStr
+			case data, ok := <-queue:
+				if !ok {
+					return
 				}
+				l.process(data)
+			}
+		}
+	}(l.context, l.queue)
+}
 
-				var separator = " "
-				switch data.valueType {
-				case valueTypeJSON:
-					separator = "\n"
-				}
+func (l *Logger) process(data *logTask) {
+	if data.flushed != nil {
+		close(data.flushed)
+		return
+	}
 
-				if extraPrettyFormat == "" {
-					for i := 0; i < len(data.values); i++ {
-						extraPrettyFormat = "%v" + separator + extraPrettyFormat
-					}
-				}
-				if extraFormat == "" {
-					for i := 0; i < len(data.values); i++ {
-						extraFormat = "%v" + " " + extraFormat
-					}
-				}
+	var format = l.infoStr
+	var formatColor = l.infoColorStr
+	var extraFormat = data.format
+	var extraPrettyFormat = data.format
+	switch data.logLevel {
+	case Debug:
+		format = l.debugStr
+		formatColor = l.debugColorStr
+	case Error:
+		format = l.errStr
+		formatColor = l.errColorStr
+	case Warn:
+		format = l.warnStr
+		formatColor = l.warnColorStr
+	}
+
+	var separator = " "
+	switch data.valueType {
+	case valueTypeJSON:
+		separator = "\n"
+	}
+
+	if l.compact {
+		separator = " "
+	}
+
+	if extraPrettyFormat == "" {
+		for i := 0; i < len(data.values); i++ {
+			extraPrettyFormat = "%v" + separator + extraPrettyFormat
+		}
+	}
+	if extraFormat == "" {
+		for i := 0; i < len(data.values); i++ {
+			extraFormat = "%v" + " " + extraFormat
+		}
+	}
+
+	extraFormat = data.decorate(extraFormat, l.redactor)
+	extraPrettyFormat = data.decorate(extraPrettyFormat, l.redactor)
+
+	var fullFormatColor = formatColor + extraPrettyFormat
+	var fullFormat = format + extraFormat
+
+	if data.requestInfo != nil {
+		var requestSeparator = "\n"
+		if l.compact {
+			requestSeparator = " "
+		}
+		fullFormatColor = data.formatRequestInfo() + requestSeparator + fullFormatColor
+		fullFormat = data.formatRequestInfo() + " " + fullFormat
+	}
+
+	var fieldValues = l.redactor.redactFields(data.values)
+
+	switch data.valueType {
+	case valueTypeCustom:
+		var customFormat = data.decorate(data.format, l.redactor)
+		l.write(l.writer, customFormat, fieldValues...)
+		if l.ignoreWriteFile(data.logLevel) == false {
 
-				var fullFormatColor = formatColor + extraPrettyFormat
-				var fullFormat = format + extraFormat
+			l.writeFile(data, customFormat, fieldValues...)
 
+			if l.notifier != nil {
+				var titleFormat = format
 				if data.requestInfo != nil {
-					fullFormatColor = data.formatRequestInfo() + "\n" + fullFormatColor
-					fullFormat = data.formatRequestInfo() + " " + fullFormat
+					titleFormat = data.formatRequestInfo() + "\n" + titleFormat
 				}
 
-				switch data.valueType {
-				case valueTypeCustom:
-					l.writer.Printf(data.format, data.values...)
-					if l.ignoreWriteFile(data.logLevel) == false {
-
-						l.writer.Printf(data.format, data.values...)
-
-						if l.notifier != nil {
-							var titleFormat = format
-							if data.requestInfo != nil {
-								titleFormat = data.formatRequestInfo() + "\n" + titleFormat
-							}
-
-							l.notifier.Send(fmt.Sprintf(titleFormat, data.time), fmt.Sprintf(data.format, data.values...))
-						}
-					}
-
-				case valueTypeJSON:
-					var prettyValues = []interface{}{}
-					var values = []interface{}{}
-					for _, value := range data.values {
-						values = append(values, ToJSONString(value))
-						prettyValues = append(prettyValues, ToPrettyJSONString(value))
-					}
-					l.writer.Printf(fullFormatColor, append([]interface{}{data.time, data.caller}, prettyValues...)...)
-					if l.ignoreWriteFile(data.logLevel) == false {
-
-						l.fileWriter.Printf(fullFormat, append([]interface{}{data.time, data.caller}, values...)...)
-
-						if l.notifier != nil {
-							var titleFormat = format
-							if data.requestInfo != nil {
-								titleFormat = data.formatRequestInfo() + "\n" + titleFormat
-							}
-
-							l.notifier.Send(fmt.Sprintf(titleFormat, data.time, data.caller), fmt.Sprintf(extraFormat, prettyValues...))
-						}
-					}
-				default:
-					l.writer.Printf(fullFormatColor, append([]interface{}{data.time, data.caller}, data.values...)...)
-					if l.ignoreWriteFile(data.logLevel) == false {
-						l.fileWriter.Printf(fullFormat, append([]interface{}{data.time, data.caller}, data.values...)...)
-						if l.notifier != nil {
-							var titleFormat = format
-							if data.requestInfo != nil {
-								titleFormat = data.formatRequestInfo() + "\n" + titleFormat
-							}
-
-							l.notifier.Send(fmt.Sprintf(titleFormat, data.time, data.caller), fmt.Sprintf(extraFormat, data.values...))
-						}
-					}
+				l.notify(data, fmt.Sprintf(titleFormat, l.formatTime(data.at), data.caller), fmt.Sprintf(customFormat, fieldValues...))
+			}
+		}
 
+	case valueTypeJSON:
+		var prettyValues = []interface{}{}
+		var values = []interface{}{}
+		for _, value := range data.values {
+			values = append(values, l.redactor.RedactJSON(value, false))
+			prettyValues = append(prettyValues, l.redactor.RedactJSON(value, !l.compact))
+		}
+		l.write(l.writer, fullFormatColor, append([]interface{}{l.formatTime(data.at), data.caller}, prettyValues...)...)
+		if l.ignoreWriteFile(data.logLevel) == false {
+
+			l.writeFile(data, fullFormat, append([]interface{}{l.formatTime(data.at), data.caller}, values...)...)
+
+			if l.notifier != nil {
+				var titleFormat = format
+				if data.requestInfo != nil {
+					titleFormat = data.formatRequestInfo() + "\n" + titleFormat
 				}
 
-				break
+				l.notify(data, fmt.Sprintf(titleFormat, l.formatTime(data.at), data.caller), fmt.Sprintf(extraFormat, prettyValues...))
 			}
 		}
-	}(l.context, l.queue)
+	default:
+		l.write(l.writer, fullFormatColor, append([]interface{}{l.formatTime(data.at), data.caller}, fieldValues...)...)
+		if l.ignoreWriteFile(data.logLevel) == false {
+			l.writeFile(data, fullFormat, append([]interface{}{l.formatTime(data.at), data.caller}, fieldValues...)...)
+			if l.notifier != nil {
+				var titleFormat = format
+				if data.requestInfo != nil {
+					titleFormat = data.formatRequestInfo() + "\n" + titleFormat
+				}
+
+				l.notify(data, fmt.Sprintf(titleFormat, l.formatTime(data.at), data.caller), fmt.Sprintf(extraFormat, fieldValues...))
+			}
+		}
+
+	}
+
+	if data.stack != "" {
+		l.write(l.writer, "%s", data.stack)
+		if l.ignoreWriteFile(data.logLevel) == false && l.config.Format != FormatJSON {
+			l.write(l.fileWriter, "%s", data.stack)
+		}
+	}
+
+	for _, sink := range l.sinks {
+		if err := sink.Write(l.entry(data)); err != nil {
+			fmt.Fprintf(os.Stderr, "logger: write sink error %v\n", err)
+		}
+	}
+}
+
+// writeFile writes to the file writer in the configured format
+func (l *Logger) writeFile(data *logTask, format string, values ...interface{}) {
+	if l.config.Format == FormatJSON {
+		l.write(l.fileWriter, "%s", (&JSONEncoder{TimeFormat: l.config.DateFormat}).Encode(l.entry(data)))
+		return
+	}
+
+	l.write(l.fileWriter, format, values...)
+}
+
+func (l *Logger) write(writer Writer, format string, values ...interface{}) {
+	if l.redactor.hasPatterns() {
+		writer.Print(l.redactor.RedactString(fmt.Sprintf(format, values...)))
+		return
+	}
+
+	writer.Printf(format, values...)
+}
+
+// notify sends the entry to the notifier, masked with the patterns of the redactor
+func (l *Logger) notify(data *logTask, title, body string) {
+	var msg = &notifier.Message{
+		Level:      data.logLevel.String(),
+		Title:      l.redactor.RedactString(title),
+		Body:       l.redactor.RedactString(body),
+		Time:       data.at,
+		Caller:     data.caller,
+		StackTrace: l.redactor.RedactString(data.stack),
+	}
+
+	if info := data.requestInfo; info != nil {
+		msg.Request = &notifier.RequestInfo{
+			ID:         info.reqID,
+			UserID:     info.userID,
+			RefErrorID: info.refErrorID,
+			Method:     info.method,
+			URI:        l.redactor.RedactString(info.uri),
+			Status:     info.status,
+			TraceID:    info.traceID,
+		}
+	}
+
+	l.notifier.Notify(msg)
+}
+
+// WithCallerSkip child logger skipping n more stack frames when resolving the caller
+func (l *Logger) WithCallerSkip(n int) *Logger {
+	var child = l.clone()
+	child.callerSkip += n
+	return child
+}
+
+// clone child logger sharing the queue, sinks and config of l
+func (l *Logger) clone() *Logger {
+	return &Logger{
+		root:       l.rootLogger(),
+		context:    l.context,
+		config:     l.config,
+		redactor:   l.rootLogger().redactor,
+		callerSkip: l.callerSkip,
+		name:       l.name,
+		prefix:     l.prefix,
+		level:      l.level,
+		fields:     l.fields[:len(l.fields):len(l.fields)],
+	}
+}
+
+func (l *Logger) rootLogger() *Logger {
+	if l.root != nil {
+		return l.root
+	}
+
+	return l
+}
+
+// entry of the task, built once for all sinks
+func (l *Logger) entry(data *logTask) *Entry {
+	if data.entry == nil {
+		data.entry = l.newEntry(data)
+	}
+
+	return data.entry
+}
+
+// Flush waits until the entries queued before the call are written, e.g. before exiting
+// without Close. It returns right away in Sync mode
+func (l *Logger) Flush() {
+	if l.root != nil {
+		l.root.Flush()
+		return
+	}
+
+	if l.config.Sync {
+		return
+	}
+
+	var task = &logTask{flushed: make(chan struct{})}
+
+	// Queued tasks are processed by Close too, the task is only waited for once queued
+	l.mutex.RLock()
+	select {
+	case l.queue <- task:
+		l.mutex.RUnlock()
+	case <-l.context.Done():
+		l.mutex.RUnlock()
+		return
+	}
+
+	<-task.flushed
+}
+
+// Close stops the logger, writes queued entries and closes the sinks and notifier
+func (l *Logger) Close() error {
+	if l.root != nil {
+		return l.root.Close()
+	}
+
+	var err error
+	l.closeOnce.Do(func() {
+		l.cancelFunc()
+		<-l.stopped
+		<-l.cleaned
+
+		for data := range l.queue {
+			l.process(data)
+		}
+
+		for _, sink := range l.sinks {
+			if e := sink.Close(); e != nil && err == nil {
+				err = e
+			}
+		}
+
+		if closer, ok := l.notifier.(interface{ Close() }); ok {
+			closer.Close()
+		}
+	})
+
+	return err
 }
 
 func (l *Logger) cleanup() {
@@ -375,6 +752,7 @@ func (l *Logger) cleanup() {
 
 	// Cleanup the destinations
 	close(l.queue)
+	close(l.cleaned)
 
 }
 
@@ -382,25 +760,37 @@ func (l *Logger) buildlog(logtype LogLevel, caller string, valueType valueType,
 	newlog = &logTask{
 		logger:    l,
 		logLevel:  logtype,
-		time:      time.Now().Format(l.config.DateFormat),
+		at:        time.Now().In(l.config.TimeLocation),
 		format:    format,
 		values:    values,
 		caller:    caller,
 		valueType: valueType,
+
+		name:         l.name,
+		prefix:       l.prefix,
+		loggerFields: l.fields,
+	}
+
+	for _, value := range values {
+		if field, ok := value.(Field); ok {
+			if detail, ok := field.Value.(*ErrorDetail); ok && detail.Stack != "" {
+				newlog.stack = detail.Stack
+				break
+			}
+		}
 	}
 
 	return newlog
 }
 
-func (l *Logger) fileWithLineNum() string {
-	for i := 2; i < 15; i++ {
-		_, file, line, ok := runtime.Caller(i)
+// formatTime formats t with DateFormat
+func (l *Logger) formatTime(t time.Time) string {
+	return FormatTime(t, l.config.DateFormat)
+}
 
-		if ok {
-			return file + ":" + strconv.FormatInt(int64(line), 10)
-		}
-	}
-	return ""
+// fileWithLineNum caller of the log function as package/file.go:line function
+func (l *Logger) fileWithLineNum() string {
+	return caller(3 + l.callerSkip)
 }
 
 func (l *Logger) ignoreWriteFile(level LogLevel) bool {
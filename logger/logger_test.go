@@ -2,6 +2,7 @@ package logger
 
 import (
 	"log"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -37,7 +38,7 @@ func TestRotateLogger(t *testing.T) {
 
 func TestLumperjackLogger(t *testing.T) {
 	var writer = &lumberjack.Logger{
-		Filename:   "foo.log",
+		Filename:   filepath.Join(t.TempDir(), "foo.log"),
 		MaxSize:    500, // megabytes
 		MaxBackups: 3,
 		MaxAge:     28,   //days
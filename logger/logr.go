@@ -0,0 +1,79 @@
+package logger
+
+import (
+	"github.com/go-logr/logr"
+)
+
+// LogrSink logr.LogSink writing to a Logger, V(0) logs at Info and higher verbosity at Debug
+type LogrSink struct {
+	logger *Logger
+	fields []Field
+	depth  int
+}
+
+// LogrSink logr.LogSink writing to l
+func (l *Logger) LogrSink() *LogrSink {
+	return &LogrSink{logger: l}
+}
+
+// Logr logr.Logger writing to l, names are named loggers of l
+func (l *Logger) Logr() logr.Logger {
+	return logr.New(l.LogrSink())
+}
+
+// Init implements logr.LogSink
+func (s *LogrSink) Init(info logr.RuntimeInfo) {
+	s.depth += info.CallDepth
+}
+
+// Enabled implements logr.LogSink
+func (s *LogrSink) Enabled(level int) bool {
+	return s.logger.Enabled(logrLevel(level))
+}
+
+// Info implements logr.LogSink
+func (s *LogrSink) Info(level int, msg string, keysAndValues ...interface{}) {
+	s.logger.logFields(nil, logrLevel(level), caller(2+s.depth), msg, s.with(keysAndValues))
+}
+
+// Error implements logr.LogSink
+func (s *LogrSink) Error(err error, msg string, keysAndValues ...interface{}) {
+	var fields = append([]Field{errorField(ErrorKey, err)}, s.with(keysAndValues)...)
+	s.logger.logFields(nil, Error, caller(2+s.depth), msg, fields)
+}
+
+// WithValues implements logr.LogSink
+func (s *LogrSink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	var sink = *s
+	sink.fields = s.with(keysAndValues)
+	return &sink
+}
+
+// WithName implements logr.LogSink
+func (s *LogrSink) WithName(name string) logr.LogSink {
+	var sink = *s
+	sink.logger = s.logger.Named(name)
+	return &sink
+}
+
+// WithCallDepth implements logr.CallDepthLogSink
+func (s *LogrSink) WithCallDepth(depth int) logr.LogSink {
+	var sink = *s
+	sink.depth += depth
+	return &sink
+}
+
+func (s *LogrSink) with(keysAndValues []interface{}) []Field {
+	var fields = s.fields[:len(s.fields):len(s.fields)]
+	return append(fields, keyValueFields(keysAndValues)...)
+}
+
+func logrLevel(level int) LogLevel {
+	if level > 0 {
+		return Debug
+	}
+
+	return Info
+}
+
+var _ logr.CallDepthLogSink = (*LogrSink)(nil)
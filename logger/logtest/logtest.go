@@ -0,0 +1,229 @@
+package logtest
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/thaitanloi365/gocore/logger"
+)
+
+// Sink capture sink recording every entry, use it with a synchronous logger to assert right after logging
+type Sink struct {
+	mutex   sync.Mutex
+	entries []*logger.Entry
+}
+
+// NewSink new capture sink
+func NewSink() *Sink {
+	return &Sink{}
+}
+
+// sinks capture sinks of the loggers created by New, by test
+var sinks = struct {
+	sync.Mutex
+	byTest map[testing.TB][]*Sink
+}{byTest: map[testing.TB][]*Sink{}}
+
+// New synchronous logger writing to a capture sink, the console output goes to t.Log
+// and the logger is closed when the test ends. options adjust the config before New
+func New(t testing.TB, options ...func(config *logger.Config)) (*logger.Logger, *Sink) {
+	var sink = NewSink()
+	var config = &logger.Config{
+		Console: &testWriter{t: t},
+		Sinks:   []logger.Sink{sink},
+	}
+	for _, option := range options {
+		option(config)
+	}
+	config.Sync = true
+
+	var l = logger.New(config)
+
+	sinks.Lock()
+	sinks.byTest[t] = append(sinks.byTest[t], sink)
+	sinks.Unlock()
+
+	t.Cleanup(func() {
+		l.Close()
+
+		sinks.Lock()
+		delete(sinks.byTest, t)
+		sinks.Unlock()
+	})
+
+	return l, sink
+}
+
+// Write implements logger.Sink
+func (s *Sink) Write(entry *logger.Entry) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+// Close implements logger.Sink
+func (s *Sink) Close() error {
+	return nil
+}
+
+// Entries recorded entries, oldest first
+func (s *Sink) Entries() []*logger.Entry {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return append([]*logger.Entry{}, s.entries...)
+}
+
+// Level recorded entries of level
+func (s *Sink) Level(level logger.LogLevel) []*logger.Entry {
+	var entries = []*logger.Entry{}
+	for _, entry := range s.Entries() {
+		if entry.Level == level {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
+// Messages messages of the recorded entries
+func (s *Sink) Messages() []string {
+	var messages = []string{}
+	for _, entry := range s.Entries() {
+		messages = append(messages, Text(entry))
+	}
+
+	return messages
+}
+
+// Reset forgets the recorded entries
+func (s *Sink) Reset() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.entries = nil
+}
+
+// Find first entry of level whose text contains substring
+func (s *Sink) Find(level logger.LogLevel, substring string) *logger.Entry {
+	for _, entry := range s.Level(level) {
+		if strings.Contains(Text(entry), substring) {
+			return entry
+		}
+	}
+
+	return nil
+}
+
+// AssertLogged asserts an entry of level containing substring was logged
+func (s *Sink) AssertLogged(t testing.TB, level logger.LogLevel, substring string) bool {
+	t.Helper()
+
+	if s.Find(level, substring) == nil {
+		t.Errorf("no %s entry containing %q was logged%s", level, substring, s.dump())
+		return false
+	}
+
+	return true
+}
+
+// AssertLogged asserts an entry of level containing substring was logged by one of
+// the loggers New created for t
+func AssertLogged(t testing.TB, level logger.LogLevel, substring string) bool {
+	t.Helper()
+
+	sinks.Lock()
+	var testSinks = sinks.byTest[t]
+	sinks.Unlock()
+
+	if len(testSinks) == 0 {
+		t.Errorf("no logger was created with logtest.New for %s", t.Name())
+		return false
+	}
+
+	var dump = ""
+	for _, sink := range testSinks {
+		if sink.Find(level, substring) != nil {
+			return true
+		}
+		dump += sink.dump()
+	}
+
+	t.Errorf("no %s entry containing %q was logged%s", level, substring, dump)
+	return false
+}
+
+// AssertNotLogged asserts no entry of level containing substring was logged
+func (s *Sink) AssertNotLogged(t testing.TB, level logger.LogLevel, substring string) bool {
+	t.Helper()
+
+	if entry := s.Find(level, substring); entry != nil {
+		t.Errorf("unexpected %s entry logged: %s", level, Text(entry))
+		return false
+	}
+
+	return true
+}
+
+// AssertField asserts an entry has the field key with value, compared by their %v formatting
+func (s *Sink) AssertField(t testing.TB, key string, value interface{}) bool {
+	t.Helper()
+
+	for _, entry := range s.Entries() {
+		for _, field := range entry.Fields {
+			if field.Key == key && fmt.Sprint(field.Value) == fmt.Sprint(value) {
+				return true
+			}
+		}
+	}
+
+	t.Errorf("no entry with field %s=%v was logged%s", key, value, s.dump())
+	return false
+}
+
+func (s *Sink) dump() string {
+	var entries = s.Entries()
+	if len(entries) == 0 {
+		return ", nothing was logged"
+	}
+
+	var lines = []string{", logged:"}
+	for _, entry := range entries {
+		lines = append(lines, fmt.Sprintf("  %s %s", entry.Level, Text(entry)))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Text message, json values and fields of the entry
+func Text(entry *logger.Entry) string {
+	var parts = []string{}
+	if entry.Message != "" {
+		parts = append(parts, entry.Message)
+	}
+	for _, value := range entry.Values {
+		parts = append(parts, string(value))
+	}
+	for _, field := range entry.Fields {
+		parts = append(parts, field.String())
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// testWriter logger.Writer writing to t.Log
+type testWriter struct {
+	t testing.TB
+}
+
+func (w *testWriter) Printf(format string, values ...interface{}) {
+	w.t.Logf(format, values...)
+}
+
+func (w *testWriter) Print(values ...interface{}) {
+	w.t.Log(values...)
+}
@@ -0,0 +1,72 @@
+package logtest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thaitanloi365/gocore/logger"
+)
+
+type recordingT struct {
+	testing.TB
+	errors []string
+}
+
+func (t *recordingT) Helper() {}
+
+func (t *recordingT) Errorf(format string, values ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, values...))
+}
+
+func TestAssertLogged(t *testing.T) {
+	var l, sink = New(t)
+
+	l.Infof("charged %d", 10)
+	l.Error("failed", logger.Any("order", 1))
+	l.DebugJSON(map[string]interface{}{"password": "secret"})
+
+	sink.AssertLogged(t, logger.Info, "charged 10")
+	sink.AssertLogged(t, logger.Error, "order=1")
+	sink.AssertLogged(t, logger.Debug, `"password":"[REDACTED]"`)
+	sink.AssertNotLogged(t, logger.Warn, "charged")
+	sink.AssertField(t, "order", 1)
+	assert.Equal(t, []string{"charged 10", "failed order=1", `{"password":"[REDACTED]"}`}, sink.Messages())
+
+	var recorder = &recordingT{TB: t}
+	assert.False(t, sink.AssertLogged(recorder, logger.Error, "charged"))
+	assert.False(t, sink.AssertNotLogged(recorder, logger.Info, "charged"))
+	assert.False(t, sink.AssertField(recorder, "order", 2))
+	assert.Len(t, recorder.errors, 3)
+	assert.Contains(t, recorder.errors[0], `no ERROR entry containing "charged" was logged, logged:`)
+	assert.Contains(t, recorder.errors[0], "INFO charged 10")
+
+	sink.Reset()
+	assert.Empty(t, sink.Entries())
+}
+
+func TestAssertLoggedByTest(t *testing.T) {
+	var l, _ = New(t)
+	var payments, _ = New(t)
+
+	l.Info("charged")
+	payments.Warn("refunded")
+
+	AssertLogged(t, logger.Info, "charged")
+	AssertLogged(t, logger.Warn, "refunded")
+
+	var other = &testing.T{}
+	assert.False(t, AssertLogged(other, logger.Info, "charged"))
+}
+
+func TestNewOptions(t *testing.T) {
+	var l, sink = New(t, func(config *logger.Config) {
+		config.Level = logger.Warn
+	})
+
+	l.Info("skipped")
+	l.Named("payments").Warn("kept")
+
+	assert.Len(t, sink.Entries(), 1)
+	assert.Equal(t, "payments", sink.Level(logger.Warn)[0].Logger)
+}
@@ -0,0 +1,165 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// RequestLoggerConfig request logger middleware config
+type RequestLoggerConfig struct {
+	Skipper middleware.Skipper
+
+	// LevelFunc maps the response status to a level, defaults to DefaultStatusLevel
+	LevelFunc func(status int) LogLevel
+
+	// MaxRequestBodySize and MaxResponseBodySize capture bodies up to the size in bytes, 0 disables capture
+	MaxRequestBodySize  int
+	MaxResponseBodySize int
+}
+
+// DefaultRequestLoggerConfig default request logger config
+var DefaultRequestLoggerConfig = RequestLoggerConfig{
+	Skipper:   middleware.DefaultSkipper,
+	LevelFunc: DefaultStatusLevel,
+}
+
+// DefaultStatusLevel 5xx Error, 4xx Warn, others Info
+func DefaultStatusLevel(status int) LogLevel {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return Error
+	case status >= http.StatusBadRequest:
+		return Warn
+	}
+
+	return Info
+}
+
+// RequestLogger logs every request once on completion
+func (l *Logger) RequestLogger() echo.MiddlewareFunc {
+	return l.RequestLoggerWithConfig(DefaultRequestLoggerConfig)
+}
+
+// RequestLoggerWithConfig logs every request once on completion with config
+func (l *Logger) RequestLoggerWithConfig(config RequestLoggerConfig) echo.MiddlewareFunc {
+	if config.Skipper == nil {
+		config.Skipper = DefaultRequestLoggerConfig.Skipper
+	}
+
+	if config.LevelFunc == nil {
+		config.LevelFunc = DefaultRequestLoggerConfig.LevelFunc
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			var req = c.Request()
+			var res = c.Response()
+			var start = time.Now()
+
+			var reqBody []byte
+			if config.MaxRequestBodySize > 0 && req.Body != nil {
+				reqBody, _ = ioutil.ReadAll(io.LimitReader(req.Body, int64(config.MaxRequestBodySize)))
+				req.Body = &readCloser{
+					Reader: io.MultiReader(bytes.NewReader(reqBody), req.Body),
+					Closer: req.Body,
+				}
+			}
+
+			var resBody *limitedBuffer
+			if config.MaxResponseBodySize > 0 {
+				resBody = &limitedBuffer{limit: config.MaxResponseBodySize}
+				res.Writer = &bodyDumpWriter{ResponseWriter: res.Writer, body: resBody}
+			}
+
+			var err = next(c)
+			if err != nil {
+				c.Error(err)
+			}
+
+			var bytesIn = req.ContentLength
+			if bytesIn < 0 {
+				bytesIn = 0
+			}
+
+			var format = "latency=%s bytes_in=%d bytes_out=%d remote_ip=%s"
+			var values = []interface{}{time.Since(start), bytesIn, res.Size, c.RealIP()}
+			if err != nil {
+				format += " error=%v"
+				values = append(values, err)
+			}
+			if len(reqBody) > 0 {
+				format += " request_body=%s"
+				values = append(values, reqBody)
+			}
+			if resBody != nil && resBody.Len() > 0 {
+				format += " response_body=%s"
+				values = append(values, resBody.String())
+			}
+
+			l.enqueue(l.buildlog(config.LevelFunc(res.Status), "", valueTypeInterface, format, values...).withEchoContext(c))
+
+			// The error was handled by c.Error, returning it would run the error handler twice
+			return nil
+		}
+	}
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// limitedBuffer keeps the first limit bytes written
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if remain := b.limit - b.Len(); remain > 0 {
+		if len(p) > remain {
+			b.Buffer.Write(p[:remain])
+		} else {
+			b.Buffer.Write(p)
+		}
+	}
+
+	return len(p), nil
+}
+
+type bodyDumpWriter struct {
+	http.ResponseWriter
+	body io.Writer
+}
+
+func (w *bodyDumpWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyDumpWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *bodyDumpWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if hijacker, ok := w.ResponseWriter.(http.Hijacker); ok {
+		return hijacker.Hijack()
+	}
+
+	return nil, nil, fmt.Errorf("response writer does not implement http.Hijacker")
+}
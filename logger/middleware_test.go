@@ -0,0 +1,111 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type captureWriter struct {
+	lines chan string
+}
+
+func newCaptureWriter() *captureWriter {
+	return &captureWriter{lines: make(chan string, 100)}
+}
+
+func (w *captureWriter) Printf(format string, values ...interface{}) {
+	w.lines <- fmt.Sprintf(format, values...)
+}
+
+func (w *captureWriter) Print(values ...interface{}) {
+	w.lines <- fmt.Sprint(values...)
+}
+
+func (w *captureWriter) next(t *testing.T) string {
+	select {
+	case line := <-w.lines:
+		return line
+	case <-time.After(time.Second):
+		t.Fatal("no log written")
+	}
+
+	return ""
+}
+
+func TestRequestLogger(t *testing.T) {
+	var writer = newCaptureWriter()
+	var logger = New(&Config{Writer: writer})
+
+	var e = echo.New()
+	e.Use(logger.RequestLoggerWithConfig(RequestLoggerConfig{
+		MaxRequestBodySize:  4,
+		MaxResponseBodySize: 5,
+		Skipper: func(c echo.Context) bool {
+			return c.Path() == "/health"
+		},
+	}))
+	e.POST("/users", func(c echo.Context) error {
+		c.Set(UserIDKey, "user-1")
+		body, _ := ioutil.ReadAll(c.Request().Body)
+		return c.String(http.StatusCreated, "created "+string(body))
+	})
+	e.GET("/missing", func(c echo.Context) error {
+		return echo.ErrNotFound
+	})
+	e.GET("/health", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	var req = httptest.NewRequest(http.MethodPost, "/users", strings.NewReader("alice"))
+	req.Header.Set(echo.HeaderXRequestID, "req-1")
+	req.Header.Set(echo.HeaderXRealIP, "10.0.0.1")
+	var rec = httptest.NewRecorder()
+	rec.Header().Set(echo.HeaderXRequestID, "req-1")
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, "created alice", rec.Body.String())
+	assert.Regexp(t, regexp.MustCompile(`^req-1 \[user-1\] 201 POST /users .+ INFO  latency=\S+ bytes_in=5 bytes_out=13 remote_ip=10\.0\.0\.1 request_body=alic response_body=creat$`), writer.next(t))
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+	assert.Regexp(t, regexp.MustCompile(`^404 GET /missing .+ WARN  latency=\S+ bytes_in=0 bytes_out=24 remote_ip=192\.0\.2\.1 error=code=404, message=Not Found response_body={"mes$`), writer.next(t))
+}
+
+func TestRequestLoggerHandlesErrorOnce(t *testing.T) {
+	var logger = New(&Config{Sync: true, Console: log.New(ioutil.Discard, "", 0)})
+	defer logger.Close()
+
+	var calls = 0
+	var e = echo.New()
+	e.HTTPErrorHandler = func(err error, c echo.Context) {
+		calls++
+		c.NoContent(http.StatusInternalServerError)
+	}
+	e.Use(logger.RequestLogger())
+	e.GET("/fail", func(c echo.Context) error {
+		return errors.New("boom")
+	})
+
+	var rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/fail", nil))
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func TestDefaultStatusLevel(t *testing.T) {
+	assert.Equal(t, Info, DefaultStatusLevel(http.StatusOK))
+	assert.Equal(t, Info, DefaultStatusLevel(http.StatusFound))
+	assert.Equal(t, Warn, DefaultStatusLevel(http.StatusNotFound))
+	assert.Equal(t, Error, DefaultStatusLevel(http.StatusBadGateway))
+}
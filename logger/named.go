@@ -0,0 +1,187 @@
+package logger
+
+import (
+	"sort"
+	"strings"
+)
+
+// NamedConfig overrides of a named logger, see Config.Loggers
+type NamedConfig struct {
+	// Level minimum level, inherits the parent level when zero
+	Level LogLevel
+	// Prefix written before every message, defaults to [name]
+	Prefix string
+	// Fields added to every entry
+	Fields map[string]interface{}
+}
+
+// NewNamedConfig new named logger config
+func NewNamedConfig() *NamedConfig {
+	return &NamedConfig{}
+}
+
+// WithLevel set minimum level
+func (c *NamedConfig) WithLevel(level LogLevel) *NamedConfig {
+	c.Level = level
+	return c
+}
+
+// WithPrefix set prefix
+func (c *NamedConfig) WithPrefix(prefix string) *NamedConfig {
+	c.Prefix = prefix
+	return c
+}
+
+// WithField add a field
+func (c *NamedConfig) WithField(key string, value interface{}) *NamedConfig {
+	if c.Fields == nil {
+		c.Fields = map[string]interface{}{}
+	}
+	c.Fields[key] = value
+	return c
+}
+
+func (c *NamedConfig) fields() []Field {
+	var keys = make([]string, 0, len(c.Fields))
+	for key := range c.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var fields = make([]Field, 0, len(keys))
+	for _, key := range keys {
+		fields = append(fields, Any(key, c.Fields[key]))
+	}
+
+	return fields
+}
+
+// Named child logger sharing the queue and sinks, configured by Config.Loggers[name].
+// Names of nested loggers are joined with a dot, the same name returns the same logger.
+// The level, fields and caller skip of derived loggers, e.g. WithLevel, apply to the
+// returned logger only
+func (l *Logger) Named(name string) *Logger {
+	if l.name != "" {
+		name = l.name + "." + name
+	}
+
+	var root = l.rootLogger()
+	root.namedMutex.Lock()
+	defer root.namedMutex.Unlock()
+
+	var base = root.registered(l.name)
+	var child = root.registered(name)
+	if l == base {
+		return child
+	}
+
+	var derived = child.clone()
+	derived.callerSkip = l.callerSkip
+	if config := root.config.Loggers[name]; l.level != base.level && (config == nil || config.Level == 0) {
+		derived.level = l.level
+	}
+	derived.fields = append(l.fields[:len(l.fields):len(l.fields)], child.fields[len(base.fields):]...)
+
+	return derived
+}
+
+// registered logger of the registry built from the root and Config.Loggers only,
+// the root for an empty name. namedMutex must be held
+func (l *Logger) registered(name string) *Logger {
+	if name == "" {
+		return l
+	}
+
+	if child, ok := l.named[name]; ok {
+		return child
+	}
+
+	var parent = l
+	if index := strings.LastIndex(name, "."); index >= 0 {
+		parent = l.registered(name[:index])
+	}
+
+	var child = parent.clone()
+	child.name = name
+	child.prefix = "[" + name + "]"
+	if config := l.config.Loggers[name]; config != nil {
+		if config.Level != 0 {
+			child.level = config.Level
+		}
+		if config.Prefix != "" {
+			child.prefix = config.Prefix
+		}
+		child.fields = append(child.fields, config.fields()...)
+	}
+
+	if l.named == nil {
+		l.named = map[string]*Logger{}
+	}
+	l.named[name] = child
+
+	return child
+}
+
+// Name name of the logger, empty for the root
+func (l *Logger) Name() string {
+	return l.name
+}
+
+// With child logger adding fields to every entry
+func (l *Logger) With(fields ...Field) *Logger {
+	var child = l.clone()
+	child.fields = append(child.fields, fields...)
+	return child
+}
+
+// WithLevel child logger skipping entries below level
+func (l *Logger) WithLevel(level LogLevel) *Logger {
+	var child = l.clone()
+	child.level = level
+	return child
+}
+
+// WithPrefix child logger writing prefix before every message
+func (l *Logger) WithPrefix(prefix string) *Logger {
+	var child = l.clone()
+	child.prefix = prefix
+	return child
+}
+
+// Enabled whether entries of level are logged
+func (l *Logger) Enabled(level LogLevel) bool {
+	return l.level == 0 || level.severity() >= l.level.severity()
+}
+
+// severity rank of the level, levels are not declared in severity order
+func (level LogLevel) severity() int {
+	switch level {
+	case Debug:
+		return 1
+	case Info:
+		return 2
+	case Warn:
+		return 3
+	case Error:
+		return 4
+	}
+
+	return int(level)
+}
+
+// decorate adds the prefix and the logger fields to a format
+func (task *logTask) decorate(format string, redactor *Redactor) string {
+	var escape = strings.NewReplacer("%", "%%")
+	if task.prefix != "" {
+		format = escape.Replace(task.prefix) + " " + format
+	}
+
+	for _, field := range task.loggerFields {
+		if redactor.IsSensitive(field.Key) {
+			field.Value = RedactedMask
+		}
+		format = strings.TrimRight(format, " ") + " " + escape.Replace(field.String())
+	}
+
+	return format
+}
@@ -0,0 +1,105 @@
+package logger
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamed(t *testing.T) {
+	var writer = newCaptureWriter()
+	var logger = New(&Config{
+		Writer: writer,
+		Level:  Info,
+		Loggers: map[string]*NamedConfig{
+			"payments": NewNamedConfig().WithLevel(Debug).WithField("service", "payments").WithField("token", "abc"),
+			"jobs":     NewNamedConfig().WithLevel(Error).WithPrefix("JOBS"),
+		},
+	})
+
+	var payments = logger.Named("payments")
+	assert.Equal(t, payments, logger.Named("payments"))
+	assert.Equal(t, "payments", payments.Name())
+
+	logger.Debug("root debug")
+	payments.Debugf("charged %d", 10)
+	assert.Regexp(t, `DEBUG .* \[payments\] charged 10 service=payments token=\[REDACTED\]$`, writer.next(t))
+
+	var jobs = logger.Named("jobs")
+	jobs.Warn("skipped")
+	jobs.Errorf("failed %s", "sync")
+	assert.Regexp(t, `ERROR .* JOBS failed sync$`, writer.next(t))
+
+	logger.Named("payments").Named("refunds").With(Any("order", 1)).Info("refunded")
+	assert.Regexp(t, `INFO .* \[payments.refunds\] refunded service=payments token=\[REDACTED\] order=1$`, writer.next(t))
+
+	logger.Close()
+	select {
+	case line := <-writer.lines:
+		t.Fatalf("unexpected line %s", line)
+	default:
+	}
+}
+
+func TestNamedDerived(t *testing.T) {
+	var writer = newCaptureWriter()
+	var logger = New(&Config{
+		Writer:  writer,
+		Loggers: map[string]*NamedConfig{"jobs": NewNamedConfig().WithLevel(Warn)},
+	})
+	defer logger.Close()
+
+	// The state of the first caller isn't cached in the registry
+	var errors = logger.WithLevel(Error).With(Any("order", 1)).Named("payments")
+	errors.Info("skipped")
+	errors.Error("failed")
+	assert.Regexp(t, `ERROR .* \[payments\] failed order=1$`, writer.next(t))
+
+	logger.Named("payments").Info("charged")
+	assert.Regexp(t, `INFO .* \[payments\] charged $`, writer.next(t))
+	assert.Equal(t, logger.Named("payments"), logger.Named("payments"))
+
+	// Config.Loggers wins over the derived level
+	logger.WithLevel(Debug).Named("jobs").Info("skipped")
+	logger.Named("jobs").With(Any("job", "sync")).Named("daily").Warn("late")
+	assert.Regexp(t, `WARN .* \[jobs.daily\] late job=sync$`, writer.next(t))
+}
+
+func TestNamedJSON(t *testing.T) {
+	var writer = newCaptureWriter()
+	var logger = New(&Config{Writer: writer, Format: FormatJSON})
+
+	logger.Named("payments").With(Any("order", 1)).Info("charged")
+
+	var object map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(writer.next(t)), &object))
+	assert.Equal(t, "payments", object["logger"])
+	assert.Equal(t, "[payments] charged", object["message"])
+	assert.Equal(t, float64(1), object["order"])
+	logger.Close()
+}
+
+func TestLevelEnabled(t *testing.T) {
+	var logger = New(&Config{Writer: newCaptureWriter(), Level: Warn})
+	defer logger.Close()
+
+	assert.False(t, logger.Enabled(Debug))
+	assert.False(t, logger.Enabled(Info))
+	assert.True(t, logger.Enabled(Warn))
+	assert.True(t, logger.Enabled(Error))
+	assert.True(t, logger.WithLevel(Debug).Enabled(Info))
+}
+
+func TestReplaceGlobal(t *testing.T) {
+	var previous = Global()
+	var logger = New(&Config{Writer: newCaptureWriter()})
+	defer logger.Close()
+
+	var restore = ReplaceGlobal(logger)
+	assert.Equal(t, logger, Global())
+	assert.Equal(t, "payments", Named("payments").Name())
+
+	restore()
+	assert.Equal(t, previous, Global())
+}
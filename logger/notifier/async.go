@@ -0,0 +1,342 @@
+package notifier
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrQueueFull returned by AsyncNotifier.Send when the queue is full
+var ErrQueueFull = errors.New("notifier queue is full")
+
+// ErrClosed returned by AsyncNotifier.Send after Close
+var ErrClosed = errors.New("notifier is closed")
+
+// AsyncConfig async notifier config
+type AsyncConfig struct {
+	QueueSize int
+
+	// RateLimit posts per second, Burst posts allowed at once
+	RateLimit float64
+	Burst     int
+
+	// BatchWindow messages received within the window are sent as one post
+	BatchWindow  time.Duration
+	MaxBatchSize int
+
+	// Deduplicate merges identical messages of a batch and appends the occurrence count
+	Deduplicate bool
+
+	// MaxRetries retries on 429/5xx and network errors, RetryBackoff doubles after every attempt
+	MaxRetries   int
+	RetryBackoff time.Duration
+
+	// OnError called when a post still fails after all retries
+	OnError func(errs []error)
+}
+
+// DefaultAsyncConfig default async config
+var DefaultAsyncConfig = AsyncConfig{
+	QueueSize:    100,
+	RateLimit:    1,
+	Burst:        3,
+	BatchWindow:  5 * time.Second,
+	MaxBatchSize: 20,
+	Deduplicate:  true,
+	MaxRetries:   3,
+	RetryBackoff: time.Second,
+}
+
+type asyncMessage struct {
+	*Message
+	count int
+}
+
+// AsyncNotifier sends messages in background with rate limiting, batching, deduplication and retries
+type AsyncNotifier struct {
+	notifier Notifier
+	config   AsyncConfig
+	bucket   *tokenBucket
+
+	queue   chan *asyncMessage
+	done    chan struct{}
+	stopped chan struct{}
+
+	closeOnce sync.Once
+	mutex     sync.RWMutex
+	closed    bool
+}
+
+// NewAsync wraps notifier, nil config uses DefaultAsyncConfig
+func NewAsync(notifier Notifier, config *AsyncConfig) *AsyncNotifier {
+	var cfg = DefaultAsyncConfig
+	if config != nil {
+		cfg = *config
+	}
+
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = DefaultAsyncConfig.QueueSize
+	}
+
+	if cfg.MaxBatchSize <= 0 {
+		cfg.MaxBatchSize = 1
+	}
+
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = DefaultAsyncConfig.RetryBackoff
+	}
+
+	var async = &AsyncNotifier{
+		notifier: notifier,
+		config:   cfg,
+		bucket:   newTokenBucket(cfg.RateLimit, cfg.Burst),
+		queue:    make(chan *asyncMessage, cfg.QueueSize),
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+
+	go async.run()
+
+	return async
+}
+
+// Notify queue msg, never blocks
+func (async *AsyncNotifier) Notify(msg *Message) []error {
+	async.mutex.RLock()
+	defer async.mutex.RUnlock()
+
+	if async.closed {
+		return []error{ErrClosed}
+	}
+
+	select {
+	case async.queue <- &asyncMessage{Message: msg, count: 1}:
+		return nil
+	default:
+		return []error{ErrQueueFull}
+	}
+}
+
+// Close sends pending messages and stops the background worker
+func (async *AsyncNotifier) Close() {
+	async.closeOnce.Do(func() {
+		async.mutex.Lock()
+		async.closed = true
+		async.mutex.Unlock()
+
+		close(async.done)
+	})
+
+	<-async.stopped
+}
+
+func (async *AsyncNotifier) run() {
+	defer close(async.stopped)
+
+	for {
+		var first *asyncMessage
+		select {
+		case first = <-async.queue:
+		case <-async.done:
+			async.drain()
+			return
+		}
+
+		var batch = []*asyncMessage{first}
+		var timer = time.NewTimer(async.config.BatchWindow)
+
+	collect:
+		for len(batch) < async.config.MaxBatchSize {
+			select {
+			case msg := <-async.queue:
+				batch = async.add(batch, msg)
+			case <-timer.C:
+				break collect
+			case <-async.done:
+				batch = async.pending(batch)
+				break collect
+			}
+		}
+		timer.Stop()
+
+		async.post(batch)
+	}
+}
+
+// pending adds queued messages to batch until it is full or the queue is empty
+func (async *AsyncNotifier) pending(batch []*asyncMessage) []*asyncMessage {
+	for len(batch) < async.config.MaxBatchSize {
+		select {
+		case msg := <-async.queue:
+			batch = async.add(batch, msg)
+		default:
+			return batch
+		}
+	}
+
+	return batch
+}
+
+func (async *AsyncNotifier) drain() {
+	for {
+		var batch = async.pending([]*asyncMessage{})
+		if len(batch) == 0 {
+			return
+		}
+		async.post(batch)
+	}
+}
+
+func (async *AsyncNotifier) add(batch []*asyncMessage, msg *asyncMessage) []*asyncMessage {
+	if async.config.Deduplicate {
+		for _, m := range batch {
+			if m.Level == msg.Level && m.Body == msg.Body {
+				m.count += msg.count
+				return batch
+			}
+		}
+	}
+
+	return append(batch, msg)
+}
+
+func (async *AsyncNotifier) post(batch []*asyncMessage) {
+	var msg = formatBatch(batch)
+
+	var errs []error
+	for attempt := 0; ; attempt++ {
+		time.Sleep(async.bucket.reserve(time.Now()))
+
+		errs = async.notifier.Notify(msg)
+		if len(errs) == 0 {
+			return
+		}
+
+		if attempt >= async.config.MaxRetries || !retryable(errs) {
+			break
+		}
+
+		time.Sleep(async.backoff(attempt, errs))
+	}
+
+	if async.config.OnError != nil {
+		async.config.OnError(errs)
+	}
+}
+
+func (async *AsyncNotifier) backoff(attempt int, errs []error) time.Duration {
+	for _, err := range errs {
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && statusErr.RetryAfter > 0 {
+			return statusErr.RetryAfter
+		}
+	}
+
+	return async.config.RetryBackoff << uint(attempt)
+}
+
+// retryable network errors and temporary status errors can be retried
+func retryable(errs []error) bool {
+	for _, err := range errs {
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && !statusErr.Temporary() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// formatBatch folds the batch into one message taking the level, request and stack
+// trace of its most severe message
+func formatBatch(batch []*asyncMessage) *Message {
+	if len(batch) == 1 {
+		var msg = *batch[0].Message
+		msg.Title += occurrences(batch[0].count)
+		return &msg
+	}
+
+	var top = batch[0]
+	var total = 0
+	var parts = []string{}
+	for _, msg := range batch {
+		if levelSeverity(msg.Level) > levelSeverity(top.Level) {
+			top = msg
+		}
+		total += msg.count
+		parts = append(parts, fmt.Sprintf("%s%s\n%s", msg.Title, occurrences(msg.count), msg.Body))
+	}
+
+	var msg = *top.Message
+	msg.Title = fmt.Sprintf("%d notifications folded (%d occurrences)", len(batch), total)
+	msg.Body = strings.Join(parts, "\n\n")
+	return &msg
+}
+
+// levelSeverity rank of the level, unknown levels rank lowest
+func levelSeverity(level string) int {
+	switch level {
+	case "ERROR":
+		return 4
+	case "WARN":
+		return 3
+	case "INFO":
+		return 2
+	case "DEBUG":
+		return 1
+	}
+
+	return 0
+}
+
+func occurrences(count int) string {
+	if count <= 1 {
+		return ""
+	}
+
+	return fmt.Sprintf(" (x%d)", count)
+}
+
+// tokenBucket rate limiter, only used by the worker goroutine
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst <= 0 {
+		burst = 1
+	}
+
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+	}
+}
+
+// reserve takes a token and returns how long to wait before using it
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	if b.rate <= 0 {
+		return 0
+	}
+
+	if !b.last.IsZero() {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+	}
+	b.last = now
+	b.tokens--
+
+	if b.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
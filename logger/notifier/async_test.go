@@ -0,0 +1,145 @@
+package notifier
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type slackServer struct {
+	*httptest.Server
+	mutex    sync.Mutex
+	texts    []string
+	requests int
+	statuses []int
+}
+
+func newSlackServer(statuses ...int) *slackServer {
+	var server = &slackServer{statuses: statuses}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.mutex.Lock()
+		defer server.mutex.Unlock()
+
+		server.requests++
+		if len(server.statuses) > 0 {
+			var status = server.statuses[0]
+			server.statuses = server.statuses[1:]
+			if status != http.StatusOK {
+				w.WriteHeader(status)
+				return
+			}
+		}
+
+		var payload struct {
+			Blocks []struct {
+				Text struct {
+					Text string `json:"text"`
+				} `json:"text"`
+			} `json:"blocks"`
+		}
+		json.NewDecoder(r.Body).Decode(&payload)
+		for _, block := range payload.Blocks {
+			server.texts = append(server.texts, block.Text.Text)
+		}
+	}))
+
+	return server
+}
+
+func (server *slackServer) result() (int, []string) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	return server.requests, append([]string{}, server.texts...)
+}
+
+func TestAsyncBatchAndDeduplicate(t *testing.T) {
+	var server = newSlackServer()
+	defer server.Close()
+
+	var async = NewAsync(New(server.URL, "", "#test"), &AsyncConfig{
+		BatchWindow:  time.Hour,
+		MaxBatchSize: 10,
+		Deduplicate:  true,
+	})
+
+	for i := 0; i < 3; i++ {
+		assert.Empty(t, async.Notify(&Message{Title: "title", Body: "same error"}))
+	}
+	assert.Empty(t, async.Notify(&Message{Title: "other", Body: "other error"}))
+	async.Close()
+
+	requests, texts := server.result()
+	assert.Equal(t, 1, requests)
+	assert.Equal(t, []string{"```2 notifications folded (4 occurrences)\ntitle (x3)\nsame error\n\nother\nother error```"}, texts)
+	assert.Equal(t, []error{ErrClosed}, async.Notify(&Message{Title: "title", Body: "body"}))
+}
+
+func TestFormatBatchLevel(t *testing.T) {
+	var msg = formatBatch([]*asyncMessage{
+		{Message: &Message{Level: "WARN", Title: "slow", Body: "slow query"}, count: 2},
+		{Message: &Message{Level: "ERROR", Title: "failed", Body: "boom", StackTrace: "main.go:1", Request: &RequestInfo{ID: "req-2"}}, count: 1},
+		{Message: &Message{Level: "INFO", Title: "done", Body: "ok"}, count: 1},
+	})
+
+	assert.Equal(t, "ERROR", msg.Level)
+	assert.Equal(t, "main.go:1", msg.StackTrace)
+	assert.Equal(t, "req-2", msg.Request.ID)
+	assert.Equal(t, "3 notifications folded (4 occurrences)", msg.Title)
+	assert.Equal(t, "slow (x2)\nslow query\n\nfailed\nboom\n\ndone\nok", msg.Body)
+}
+
+func TestAsyncRetry(t *testing.T) {
+	var server = newSlackServer(http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusOK)
+	defer server.Close()
+
+	var async = NewAsync(New(server.URL, "", "#test"), &AsyncConfig{
+		MaxBatchSize: 1,
+		MaxRetries:   3,
+		RetryBackoff: time.Millisecond,
+	})
+	async.Notify(&Message{Title: "title", Body: "body"})
+	async.Close()
+
+	requests, texts := server.result()
+	assert.Equal(t, 3, requests)
+	assert.Equal(t, []string{"```title\nbody```"}, texts)
+}
+
+func TestAsyncNoRetryOnClientError(t *testing.T) {
+	var server = newSlackServer(http.StatusBadRequest)
+	defer server.Close()
+
+	var failed []error
+	var async = NewAsync(New(server.URL, "", "#test"), &AsyncConfig{
+		MaxBatchSize: 1,
+		MaxRetries:   3,
+		RetryBackoff: time.Millisecond,
+		OnError: func(errs []error) {
+			failed = errs
+		},
+	})
+	async.Notify(&Message{Title: "title", Body: "body"})
+	async.Close()
+
+	requests, _ := server.result()
+	assert.Equal(t, 1, requests)
+	if assert.Len(t, failed, 1) {
+		assert.Equal(t, http.StatusBadRequest, failed[0].(*StatusError).StatusCode)
+	}
+}
+
+func TestTokenBucket(t *testing.T) {
+	var bucket = newTokenBucket(2, 2)
+	var now = time.Now()
+
+	assert.Equal(t, time.Duration(0), bucket.reserve(now))
+	assert.Equal(t, time.Duration(0), bucket.reserve(now))
+	assert.Equal(t, 500*time.Millisecond, bucket.reserve(now))
+	assert.Equal(t, time.Duration(0), bucket.reserve(now.Add(time.Second)))
+}
@@ -0,0 +1,63 @@
+package notifier
+
+import (
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Discord limits embed titles to 256 and descriptions to 4096 characters
+const (
+	discordMaxTitle       = 256
+	discordMaxDescription = 4096
+)
+
+// DiscordNotifier Discord webhook
+type DiscordNotifier struct {
+	WebhookURL string
+	ProxyURL   string
+	Username   string
+}
+
+// NewDiscord instance
+func NewDiscord(webhookURL, proxyURL, username string) *DiscordNotifier {
+	return &DiscordNotifier{
+		WebhookURL: webhookURL,
+		ProxyURL:   proxyURL,
+		Username:   username,
+	}
+}
+
+// Notify implements Notifier
+func (discord *DiscordNotifier) Notify(msg *Message) []error {
+	var color, _ = strconv.ParseInt(levelColor(msg.Level)[1:], 16, 64)
+	var description = fmt.Sprintf("```%s```", truncate(msg.Body, discordMaxDescription-6))
+
+	var payload = map[string]interface{}{
+		"embeds": []map[string]interface{}{
+			{
+				"title":       truncate(msg.Title, discordMaxTitle),
+				"description": description,
+				"color":       color,
+			},
+		},
+	}
+	if discord.Username != "" {
+		payload["username"] = discord.Username
+	}
+
+	return postJSON(discord.WebhookURL, discord.ProxyURL, payload)
+}
+
+// truncate cuts value to max characters ending with "...", multi-byte characters are kept whole
+func truncate(value string, max int) string {
+	if utf8.RuneCountInString(value) <= max {
+		return value
+	}
+
+	if max < 3 {
+		max = 3
+	}
+
+	return string([]rune(value)[:max-3]) + "..."
+}
@@ -0,0 +1,37 @@
+package notifier
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// StatusError error returned when the webhook responds with an error status
+type StatusError struct {
+	StatusCode int
+	Status     string
+	RetryAfter time.Duration
+}
+
+func newStatusError(resp *http.Response) *StatusError {
+	var err = &StatusError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+	}
+
+	if seconds, e := strconv.Atoi(resp.Header.Get("Retry-After")); e == nil && seconds > 0 {
+		err.RetryAfter = time.Duration(seconds) * time.Second
+	}
+
+	return err
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("Error sending msg. Status: %v", e.Status)
+}
+
+// Temporary reports whether the request can be retried (429 or 5xx)
+func (e *StatusError) Temporary() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
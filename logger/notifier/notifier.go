@@ -0,0 +1,73 @@
+package notifier
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/parnurzeal/gorequest"
+)
+
+// Notifier sends log messages to a chat channel or webhook
+type Notifier interface {
+	Notify(msg *Message) []error
+}
+
+// Message notification message
+type Message struct {
+	Level      string       `json:"level"`
+	Title      string       `json:"title"`
+	Body       string       `json:"body"`
+	Time       time.Time    `json:"time"`
+	Caller     string       `json:"caller,omitempty"`
+	Request    *RequestInfo `json:"request,omitempty"`
+	StackTrace string       `json:"stack_trace,omitempty"`
+}
+
+// RequestInfo http request the message was logged for
+type RequestInfo struct {
+	ID         string `json:"id,omitempty"`
+	UserID     string `json:"user_id,omitempty"`
+	RefErrorID string `json:"ref_error_id,omitempty"`
+	Method     string `json:"method,omitempty"`
+	URI        string `json:"uri,omitempty"`
+	Status     int    `json:"status,omitempty"`
+	TraceID    string `json:"trace_id,omitempty"`
+}
+
+func redirectPolicyFunc(req gorequest.Request, via []gorequest.Request) error {
+	return fmt.Errorf("Incorrect token (redirection)")
+}
+
+func postJSON(url, proxyURL string, payload interface{}) []error {
+	request := gorequest.New().Proxy(proxyURL)
+	resp, _, err := request.
+		Post(url).
+		RedirectPolicy(redirectPolicyFunc).
+		Send(payload).
+		End()
+
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 400 {
+		return []error{newStatusError(resp)}
+	}
+
+	return nil
+}
+
+// levelColor hex color of the level, used by notifiers supporting colored messages
+func levelColor(level string) string {
+	switch level {
+	case "ERROR":
+		return "#E01E5A"
+	case "WARN":
+		return "#ECB22E"
+	case "INFO":
+		return "#36C5F0"
+	case "DEBUG":
+		return "#2EB67D"
+	}
+
+	return "#9E9E9E"
+}
@@ -0,0 +1,157 @@
+package notifier
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type capturedRequest struct {
+	method string
+	path   string
+	header http.Header
+	body   []byte
+}
+
+func newCaptureServer(status int) (*httptest.Server, chan *capturedRequest) {
+	var requests = make(chan *capturedRequest, 10)
+	var server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		requests <- &capturedRequest{
+			method: r.Method,
+			path:   r.URL.Path,
+			header: r.Header,
+			body:   body,
+		}
+		w.WriteHeader(status)
+	}))
+
+	return server, requests
+}
+
+func decode(t *testing.T, data []byte) map[string]interface{} {
+	var payload = map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(data, &payload))
+	return payload
+}
+
+var testMessage = &Message{
+	Level: "ERROR",
+	Title: "2022-01-01 ERROR main.go:10",
+	Body:  "something <failed>",
+	Time:  time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+}
+
+func TestTeamsNotifier(t *testing.T) {
+	server, requests := newCaptureServer(http.StatusOK)
+	defer server.Close()
+
+	assert.Empty(t, NewTeams(server.URL, "").Notify(testMessage))
+
+	var payload = decode(t, (<-requests).body)
+	assert.Equal(t, "MessageCard", payload["@type"])
+	assert.Equal(t, "E01E5A", payload["themeColor"])
+	assert.Equal(t, testMessage.Title, payload["title"])
+	assert.Equal(t, "<pre>something &lt;failed&gt;</pre>", payload["text"])
+}
+
+func TestDiscordNotifier(t *testing.T) {
+	server, requests := newCaptureServer(http.StatusNoContent)
+	defer server.Close()
+
+	assert.Empty(t, NewDiscord(server.URL, "", "logger").Notify(testMessage))
+
+	var payload = decode(t, (<-requests).body)
+	assert.Equal(t, "logger", payload["username"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{
+			"title":       testMessage.Title,
+			"description": "```something <failed>```",
+			"color":       float64(0xE01E5A),
+		},
+	}, payload["embeds"])
+
+	// Discord rejects titles over 256 characters, e.g. long request URIs
+	assert.Empty(t, NewDiscord(server.URL, "", "").Notify(&Message{Title: "GET /" + strings.Repeat("é", 300), Body: "boom"}))
+	var title = decode(t, (<-requests).body)["embeds"].([]interface{})[0].(map[string]interface{})["title"].(string)
+	assert.Equal(t, discordMaxTitle, utf8.RuneCountInString(title))
+	assert.True(t, utf8.ValidString(title))
+}
+
+func TestTelegramNotifier(t *testing.T) {
+	server, requests := newCaptureServer(http.StatusOK)
+	defer server.Close()
+
+	var telegram = NewTelegram("token", "-100", "")
+	telegram.APIURL = server.URL
+	assert.Empty(t, telegram.Notify(testMessage))
+
+	var request = <-requests
+	assert.Equal(t, "/bottoken/sendMessage", request.path)
+
+	var payload = decode(t, request.body)
+	assert.Equal(t, "-100", payload["chat_id"])
+	assert.Equal(t, "HTML", payload["parse_mode"])
+	assert.Equal(t, "<b>2022-01-01 ERROR main.go:10</b>\n<pre>something &lt;failed&gt;</pre>", payload["text"])
+
+	// Long titles are truncated before the body
+	assert.Empty(t, telegram.Notify(&Message{Title: strings.Repeat("a", 5000), Body: strings.Repeat("b", 5000)}))
+	var text = decode(t, (<-requests).body)["text"].(string)
+	assert.LessOrEqual(t, len(text), telegramMaxText)
+	assert.Contains(t, text, "bb...</pre>")
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	server, requests := newCaptureServer(http.StatusOK)
+	defer server.Close()
+
+	webhook, err := NewWebhook(server.URL, "", "")
+	assert.NoError(t, err)
+	webhook.WithHeader("Authorization", "Bearer secret")
+	assert.Empty(t, webhook.Notify(testMessage))
+
+	var request = <-requests
+	assert.Equal(t, http.MethodPost, request.method)
+	assert.Equal(t, "application/json", request.header.Get("Content-Type"))
+	assert.Equal(t, "Bearer secret", request.header.Get("Authorization"))
+	assert.Equal(t, map[string]interface{}{
+		"level": "ERROR",
+		"title": testMessage.Title,
+		"body":  testMessage.Body,
+		"time":  "2022-01-01T00:00:00Z",
+	}, decode(t, request.body))
+}
+
+func TestWebhookNotifierTemplate(t *testing.T) {
+	server, requests := newCaptureServer(http.StatusOK)
+	defer server.Close()
+
+	webhook, err := NewWebhook(server.URL, "", `{"text": {{json (printf "[%s] %s" .Level .Body)}}}`)
+	assert.NoError(t, err)
+	webhook.Method = http.MethodPut
+	assert.Empty(t, webhook.Notify(testMessage))
+
+	var request = <-requests
+	assert.Equal(t, http.MethodPut, request.method)
+	assert.Equal(t, map[string]interface{}{"text": "[ERROR] something <failed>"}, decode(t, request.body))
+
+	_, err = NewWebhook(server.URL, "", "{{")
+	assert.Error(t, err)
+}
+
+func TestNotifierStatusError(t *testing.T) {
+	server, _ := newCaptureServer(http.StatusServiceUnavailable)
+	defer server.Close()
+
+	var errs = NewDiscord(server.URL, "", "").Notify(testMessage)
+	if assert.Len(t, errs, 1) {
+		assert.True(t, errs[0].(*StatusError).Temporary())
+	}
+}
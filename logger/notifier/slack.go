@@ -1,20 +1,13 @@
 package notifier
 
-import (
-	"fmt"
-
-	"github.com/parnurzeal/gorequest"
-)
-
-func redirectPolicyFunc(req gorequest.Request, via []gorequest.Request) error {
-	return fmt.Errorf("Incorrect token (redirection)")
-}
-
 // SlackNotifier instance
 type SlackNotifier struct {
 	WebhookURL string
 	ProxyURL   string
 	Channel    string
+
+	// Formatter builds the payload, defaults to CodeBlockFormatter
+	Formatter SlackFormatter
 }
 
 // New instance
@@ -26,34 +19,28 @@ func New(webhookURL, proxyURL, channel string) *SlackNotifier {
 	}
 }
 
+// WithFormatter overrides the payload formatter
+func (slack *SlackNotifier) WithFormatter(formatter SlackFormatter) *SlackNotifier {
+	slack.Formatter = formatter
+	return slack
+}
+
 // Send send msg
 func (slack *SlackNotifier) Send(title, body string) []error {
-	var payload = map[string]interface{}{
-		"channel": slack.Channel,
-		"blocks": []map[string]interface{}{
-			{
-				"type": "section",
-				"text": map[string]interface{}{
-
-					"type": "mrkdwn",
-					"text": fmt.Sprintf("```%s\n%s```", title, body),
-				},
-			},
-		},
-	}
-	request := gorequest.New().Proxy(slack.ProxyURL)
-	resp, _, err := request.
-		Post(slack.WebhookURL).
-		RedirectPolicy(redirectPolicyFunc).
-		Send(payload).
-		End()
-
-	if err != nil {
-		return err
+	return slack.Notify(&Message{Title: title, Body: body})
+}
+
+// Notify implements Notifier
+func (slack *SlackNotifier) Notify(msg *Message) []error {
+	var formatter = slack.Formatter
+	if formatter == nil {
+		formatter = CodeBlockFormatter{}
 	}
-	if resp.StatusCode >= 400 {
-		return []error{fmt.Errorf("Error sending msg. Status: %v", resp.Status)}
+
+	var payload = formatter.Format(msg)
+	if slack.Channel != "" {
+		payload["channel"] = slack.Channel
 	}
 
-	return nil
+	return postJSON(slack.WebhookURL, slack.ProxyURL, payload)
 }
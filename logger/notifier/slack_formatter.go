@@ -0,0 +1,187 @@
+package notifier
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Slack limits
+const (
+	slackMaxHeader  = 150
+	slackMaxText    = 3000
+	slackMaxTitle   = 1000
+	slackMaxFields  = 10
+	slackStackLines = 50
+)
+
+// SlackFormatter builds the slack webhook payload of a message
+type SlackFormatter interface {
+	Format(msg *Message) map[string]interface{}
+}
+
+// SlackFormatterFunc adapts a func to SlackFormatter
+type SlackFormatterFunc func(msg *Message) map[string]interface{}
+
+// Format implements SlackFormatter
+func (f SlackFormatterFunc) Format(msg *Message) map[string]interface{} {
+	return f(msg)
+}
+
+// CodeBlockFormatter sends title and body in one code block
+type CodeBlockFormatter struct{}
+
+// Format implements SlackFormatter
+func (CodeBlockFormatter) Format(msg *Message) map[string]interface{} {
+	return map[string]interface{}{
+		"blocks": []map[string]interface{}{
+			{
+				"type": "section",
+				"text": map[string]interface{}{
+
+					"type": "mrkdwn",
+					"text": fmt.Sprintf("```%s\n%s```", msg.Title, msg.Body),
+				},
+			},
+		},
+	}
+}
+
+// BlockKitFormatter sends a Block Kit message with a level colored header,
+// request fields, the stack trace in a collapsible attachment and a link to the logs
+type BlockKitFormatter struct {
+	Service     string
+	Environment string
+
+	// LogsURL link to the logs, {request_id} is replaced by the request ID
+	LogsURL string
+}
+
+// Format implements SlackFormatter
+func (f BlockKitFormatter) Format(msg *Message) map[string]interface{} {
+	var blocks = []map[string]interface{}{
+		{
+			"type": "header",
+			"text": plainText(truncate(strings.TrimSpace(msg.Level+" "+f.Service), slackMaxHeader)),
+		},
+	}
+
+	if fields := f.fields(msg); len(fields) > 0 {
+		blocks = append(blocks, map[string]interface{}{
+			"type":   "section",
+			"fields": fields,
+		})
+	}
+
+	var text = truncate(msg.Title, slackMaxTitle)
+	if msg.Body != "" {
+		text = fmt.Sprintf("%s\n```%s```", text, truncate(msg.Body, slackMaxText-utf8.RuneCountInString(text)-8))
+	}
+	blocks = append(blocks, map[string]interface{}{
+		"type": "section",
+		"text": markdown(text),
+	})
+
+	if url := f.logsURL(msg); url != "" {
+		blocks = append(blocks, map[string]interface{}{
+			"type": "actions",
+			"elements": []map[string]interface{}{
+				{
+					"type": "button",
+					"text": plainText("View logs"),
+					"url":  url,
+				},
+			},
+		})
+	}
+
+	var attachments = []map[string]interface{}{
+		{
+			"color":  levelColor(msg.Level),
+			"blocks": blocks,
+		},
+	}
+
+	// Slack collapses long attachment text behind "Show more"
+	if msg.StackTrace != "" {
+		attachments = append(attachments, map[string]interface{}{
+			"color":     levelColor(msg.Level),
+			"title":     "Stack trace",
+			"text":      fmt.Sprintf("```%s```", truncateLines(msg.StackTrace, slackStackLines)),
+			"mrkdwn_in": []string{"text"},
+		})
+	}
+
+	return map[string]interface{}{
+		"text":        fmt.Sprintf("%s %s", msg.Level, msg.Title),
+		"attachments": attachments,
+	}
+}
+
+func (f BlockKitFormatter) fields(msg *Message) []map[string]interface{} {
+	var fields = []map[string]interface{}{}
+	var add = func(name, value string) {
+		if value != "" && len(fields) < slackMaxFields {
+			fields = append(fields, markdown(fmt.Sprintf("*%s*\n%s", name, value)))
+		}
+	}
+
+	add("Service", f.Service)
+	add("Environment", f.Environment)
+	if !msg.Time.IsZero() {
+		add("Time", msg.Time.Format("2006-01-02 15:04:05 Z07:00"))
+	}
+	add("Caller", msg.Caller)
+
+	if req := msg.Request; req != nil {
+		add("Request ID", req.ID)
+		add("User ID", req.UserID)
+		add("Ref Error ID", req.RefErrorID)
+		if req.Method != "" {
+			add("Request", fmt.Sprintf("`%s %s`", req.Method, req.URI))
+		}
+		if req.Status > 0 {
+			add("Status", strconv.Itoa(req.Status))
+		}
+	}
+
+	return fields
+}
+
+func (f BlockKitFormatter) logsURL(msg *Message) string {
+	if f.LogsURL == "" {
+		return ""
+	}
+
+	var requestID = ""
+	if msg.Request != nil {
+		requestID = msg.Request.ID
+	}
+
+	return strings.Replace(f.LogsURL, "{request_id}", requestID, -1)
+}
+
+func plainText(text string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":  "plain_text",
+		"text":  text,
+		"emoji": true,
+	}
+}
+
+func markdown(text string) map[string]interface{} {
+	return map[string]interface{}{
+		"type": "mrkdwn",
+		"text": text,
+	}
+}
+
+func truncateLines(value string, max int) string {
+	var lines = strings.Split(value, "\n")
+	if len(lines) <= max {
+		return truncate(value, slackMaxText)
+	}
+
+	return truncate(strings.Join(lines[:max], "\n")+"\n...", slackMaxText)
+}
@@ -0,0 +1,98 @@
+package notifier
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockKitFormatter(t *testing.T) {
+	var formatter = BlockKitFormatter{
+		Service:     "api",
+		Environment: "staging",
+		LogsURL:     "https://logs.example.com/?q={request_id}",
+	}
+
+	var payload = formatter.Format(&Message{
+		Level:      "ERROR",
+		Title:      "Create order failed",
+		Body:       "record not found",
+		Caller:     "order.go:42",
+		StackTrace: strings.Repeat("main.go:1\n", 60),
+		Request: &RequestInfo{
+			ID:     "req-1",
+			UserID: "user-1",
+			Method: http.MethodPost,
+			URI:    "/orders",
+			Status: http.StatusInternalServerError,
+		},
+	})
+
+	var attachments = payload["attachments"].([]map[string]interface{})
+	assert.Len(t, attachments, 2)
+	assert.Equal(t, "#E01E5A", attachments[0]["color"])
+
+	var blocks = attachments[0]["blocks"].([]map[string]interface{})
+	assert.Equal(t, "header", blocks[0]["type"])
+	assert.Equal(t, "ERROR api", blocks[0]["text"].(map[string]interface{})["text"])
+
+	var fields = []string{}
+	for _, field := range blocks[1]["fields"].([]map[string]interface{}) {
+		fields = append(fields, field["text"].(string))
+	}
+	assert.Equal(t, []string{
+		"*Service*\napi",
+		"*Environment*\nstaging",
+		"*Caller*\norder.go:42",
+		"*Request ID*\nreq-1",
+		"*User ID*\nuser-1",
+		"*Request*\n`POST /orders`",
+		"*Status*\n500",
+	}, fields)
+
+	assert.Equal(t, "Create order failed\n```record not found```", blocks[2]["text"].(map[string]interface{})["text"])
+	assert.Equal(t, "https://logs.example.com/?q=req-1", blocks[3]["elements"].([]map[string]interface{})[0]["url"])
+
+	var stack = attachments[1]["text"].(string)
+	assert.Equal(t, 50, strings.Count(stack, "\n"))
+	assert.True(t, strings.HasSuffix(stack, "...```"))
+}
+
+func TestBlockKitFormatterLongTitle(t *testing.T) {
+	var payload = BlockKitFormatter{}.Format(&Message{
+		Level: "ERROR",
+		Title: "GET /" + strings.Repeat("a", 5000),
+		Body:  strings.Repeat("b", 5000),
+	})
+
+	var blocks = payload["attachments"].([]map[string]interface{})[0]["blocks"].([]map[string]interface{})
+	var text = blocks[len(blocks)-1]["text"].(map[string]interface{})["text"].(string)
+	assert.LessOrEqual(t, len(text), slackMaxText)
+	assert.True(t, strings.HasPrefix(text, "GET /aaa"))
+	assert.True(t, strings.HasSuffix(text, "bb...```"))
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "abc", truncate("abc", 3))
+	assert.Equal(t, "a...", truncate("abcdef", 4))
+	assert.Equal(t, "...", truncate("abcdef", -100))
+	assert.Equal(t, "héé...", truncate("hééllo wörld", 6))
+	assert.Equal(t, "hé", truncate("hé", 2))
+}
+
+func TestSlackNotifierFormatter(t *testing.T) {
+	server, requests := newCaptureServer(http.StatusOK)
+	defer server.Close()
+
+	var slack = New(server.URL, "", "#alerts").WithFormatter(SlackFormatterFunc(func(msg *Message) map[string]interface{} {
+		return map[string]interface{}{"text": msg.Level + " " + msg.Body}
+	}))
+	assert.Empty(t, slack.Notify(&Message{Level: "WARN", Body: "slow query"}))
+
+	assert.Equal(t, map[string]interface{}{
+		"channel": "#alerts",
+		"text":    "WARN slow query",
+	}, decode(t, (<-requests).body))
+}
@@ -0,0 +1,34 @@
+package notifier
+
+import (
+	"fmt"
+	"html"
+)
+
+// TeamsNotifier Microsoft Teams incoming webhook
+type TeamsNotifier struct {
+	WebhookURL string
+	ProxyURL   string
+}
+
+// NewTeams instance
+func NewTeams(webhookURL, proxyURL string) *TeamsNotifier {
+	return &TeamsNotifier{
+		WebhookURL: webhookURL,
+		ProxyURL:   proxyURL,
+	}
+}
+
+// Notify implements Notifier
+func (teams *TeamsNotifier) Notify(msg *Message) []error {
+	var payload = map[string]interface{}{
+		"@type":      "MessageCard",
+		"@context":   "https://schema.org/extensions",
+		"summary":    msg.Title,
+		"themeColor": levelColor(msg.Level)[1:],
+		"title":      msg.Title,
+		"text":       fmt.Sprintf("<pre>%s</pre>", html.EscapeString(msg.Body)),
+	}
+
+	return postJSON(teams.WebhookURL, teams.ProxyURL, payload)
+}
@@ -0,0 +1,56 @@
+package notifier
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"unicode/utf8"
+)
+
+// Telegram limits messages to 4096 characters
+const (
+	telegramMaxText  = 4096
+	telegramMaxTitle = 512
+)
+
+// DefaultTelegramAPIURL Telegram bot API
+const DefaultTelegramAPIURL = "https://api.telegram.org"
+
+// TelegramNotifier Telegram bot
+type TelegramNotifier struct {
+	BotToken string
+	ChatID   string
+	ProxyURL string
+
+	// APIURL overrides DefaultTelegramAPIURL
+	APIURL string
+}
+
+// NewTelegram instance
+func NewTelegram(botToken, chatID, proxyURL string) *TelegramNotifier {
+	return &TelegramNotifier{
+		BotToken: botToken,
+		ChatID:   chatID,
+		ProxyURL: proxyURL,
+	}
+}
+
+// Notify implements Notifier
+func (telegram *TelegramNotifier) Notify(msg *Message) []error {
+	var apiURL = telegram.APIURL
+	if apiURL == "" {
+		apiURL = DefaultTelegramAPIURL
+	}
+
+	var title = html.EscapeString(truncate(msg.Title, telegramMaxTitle))
+	var body = html.EscapeString(truncate(msg.Body, telegramMaxText-utf8.RuneCountInString(title)-32))
+
+	var payload = map[string]interface{}{
+		"chat_id":    telegram.ChatID,
+		"parse_mode": "HTML",
+		"text":       fmt.Sprintf("<b>%s</b>\n<pre>%s</pre>", title, body),
+	}
+
+	var url = fmt.Sprintf("%s/bot%s/sendMessage", strings.TrimSuffix(apiURL, "/"), telegram.BotToken)
+	return postJSON(url, telegram.ProxyURL, payload)
+}
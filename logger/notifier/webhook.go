@@ -0,0 +1,84 @@
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"text/template"
+
+	"github.com/parnurzeal/gorequest"
+)
+
+// DefaultWebhookTemplate posts the message as json
+const DefaultWebhookTemplate = `{"level":{{json .Level}},"title":{{json .Title}},"body":{{json .Body}},"time":{{json .Time}}}`
+
+var webhookFuncs = template.FuncMap{
+	"json": func(value interface{}) (string, error) {
+		data, err := json.Marshal(value)
+		return string(data), err
+	},
+}
+
+// WebhookNotifier posts messages rendered with a text/template to any webhook
+type WebhookNotifier struct {
+	URL         string
+	ProxyURL    string
+	Method      string
+	ContentType string
+	Headers     map[string]string
+
+	template *template.Template
+}
+
+// NewWebhook instance, tmpl is rendered with *Message, empty uses DefaultWebhookTemplate.
+// The json function escapes values, e.g. {"text": {{json .Body}}}
+func NewWebhook(url, proxyURL, tmpl string) (*WebhookNotifier, error) {
+	if tmpl == "" {
+		tmpl = DefaultWebhookTemplate
+	}
+
+	t, err := template.New("webhook").Funcs(webhookFuncs).Parse(tmpl)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WebhookNotifier{
+		URL:         url,
+		ProxyURL:    proxyURL,
+		Method:      gorequest.POST,
+		ContentType: "application/json",
+		Headers:     map[string]string{},
+		template:    t,
+	}, nil
+}
+
+// WithHeader adds a request header
+func (webhook *WebhookNotifier) WithHeader(key, value string) *WebhookNotifier {
+	webhook.Headers[key] = value
+	return webhook
+}
+
+// Notify implements Notifier
+func (webhook *WebhookNotifier) Notify(msg *Message) []error {
+	var body bytes.Buffer
+	if err := webhook.template.Execute(&body, msg); err != nil {
+		return []error{err}
+	}
+
+	var request = gorequest.New().Proxy(webhook.ProxyURL).
+		CustomMethod(webhook.Method, webhook.URL).
+		RedirectPolicy(redirectPolicyFunc).
+		Set("Content-Type", webhook.ContentType)
+	for key, value := range webhook.Headers {
+		request = request.Set(key, value)
+	}
+
+	resp, _, errs := request.Type("text").Send(body.String()).End()
+	if errs != nil {
+		return errs
+	}
+	if resp.StatusCode >= 400 {
+		return []error{newStatusError(resp)}
+	}
+
+	return nil
+}
@@ -0,0 +1,391 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DefaultOTLPEndpoint OTLP/HTTP logs endpoint of a local collector
+const DefaultOTLPEndpoint = "http://localhost:4318/v1/logs"
+
+const otlpScopeName = "github.com/thaitanloi365/gocore/logger"
+
+// OTLPConfig OTLP exporter config
+type OTLPConfig struct {
+	// Endpoint OTLP/HTTP logs endpoint, defaults to DefaultOTLPEndpoint
+	Endpoint string
+	Headers  map[string]string
+
+	// ServiceName and ResourceAttributes describe the resource of every log record
+	ServiceName        string
+	ResourceAttributes map[string]string
+
+	// BatchSize records per export, FlushInterval exports incomplete batches
+	BatchSize     int
+	FlushInterval time.Duration
+	// MaxQueueSize records kept while the collector is unreachable, newer records are dropped
+	MaxQueueSize int
+
+	Client *http.Client
+}
+
+// OTLPSink exports entries as OTLP/HTTP json log records in background
+type OTLPSink struct {
+	config *OTLPConfig
+
+	mutex   sync.Mutex
+	pending []*Entry
+	dropped uint64
+
+	flush   chan struct{}
+	done    chan struct{}
+	stopped chan struct{}
+	once    sync.Once
+}
+
+// NewOTLPSink new OTLP exporter sink
+func NewOTLPSink(config *OTLPConfig) *OTLPSink {
+	var cfg = *config
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = DefaultOTLPEndpoint
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = 5 * time.Second
+	}
+	if cfg.MaxQueueSize <= 0 {
+		cfg.MaxQueueSize = 2048
+	}
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	var sink = &OTLPSink{
+		config:  &cfg,
+		flush:   make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+
+	go sink.run()
+
+	return sink
+}
+
+// Write implements Sink, the entry is exported with the next batch
+func (s *OTLPSink) Write(entry *Entry) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(s.pending) >= s.config.MaxQueueSize {
+		s.dropped++
+		return nil
+	}
+
+	s.pending = append(s.pending, entry)
+	if len(s.pending) >= s.config.BatchSize {
+		select {
+		case s.flush <- struct{}{}:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// Close implements Sink, pending entries are exported
+func (s *OTLPSink) Close() error {
+	s.once.Do(func() {
+		close(s.done)
+	})
+	<-s.stopped
+
+	return nil
+}
+
+func (s *OTLPSink) run() {
+	defer close(s.stopped)
+
+	var ticker = time.NewTicker(s.config.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			s.export()
+			return
+		case <-ticker.C:
+			s.export()
+		case <-s.flush:
+			s.export()
+		}
+	}
+}
+
+// export posts the pending entries in batches
+func (s *OTLPSink) export() {
+	for {
+		s.mutex.Lock()
+		var batch = s.pending
+		if len(batch) > s.config.BatchSize {
+			batch = batch[:s.config.BatchSize]
+		}
+		s.pending = s.pending[len(batch):]
+		var dropped = s.dropped
+		s.dropped = 0
+		s.mutex.Unlock()
+
+		if dropped > 0 {
+			fmt.Fprintf(os.Stderr, "logger: otlp queue full, dropped %d log records\n", dropped)
+		}
+
+		if len(batch) == 0 {
+			return
+		}
+
+		if err := s.post(batch); err != nil {
+			fmt.Fprintf(os.Stderr, "logger: otlp export error %v\n", err)
+		}
+	}
+}
+
+func (s *OTLPSink) post(batch []*Entry) error {
+	data, err := json.Marshal(s.payload(batch))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.config.Endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range s.config.Headers {
+		req.Header.Set(key, value)
+	}
+
+	res, err := s.config.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		return fmt.Errorf("%s responded %s", s.config.Endpoint, res.Status)
+	}
+
+	return nil
+}
+
+func (s *OTLPSink) payload(batch []*Entry) map[string]interface{} {
+	var resource = []map[string]interface{}{}
+	if s.config.ServiceName != "" {
+		resource = append(resource, otlpAttribute("service.name", s.config.ServiceName))
+	}
+	var keys = make([]string, 0, len(s.config.ResourceAttributes))
+	for key := range s.config.ResourceAttributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		resource = append(resource, otlpAttribute(key, s.config.ResourceAttributes[key]))
+	}
+
+	var records = make([]map[string]interface{}, 0, len(batch))
+	for _, entry := range batch {
+		records = append(records, otlpRecord(entry))
+	}
+
+	return map[string]interface{}{
+		"resourceLogs": []interface{}{
+			map[string]interface{}{
+				"resource": map[string]interface{}{"attributes": resource},
+				"scopeLogs": []interface{}{
+					map[string]interface{}{
+						"scope":      map[string]interface{}{"name": otlpScopeName},
+						"logRecords": records,
+					},
+				},
+			},
+		},
+	}
+}
+
+// otlpRecord log record of the OTLP json encoding, ids are hex and 64-bit integers are strings
+func otlpRecord(entry *Entry) map[string]interface{} {
+	var attributes = []map[string]interface{}{}
+	if entry.Logger != "" {
+		attributes = append(attributes, otlpAttribute("logger.name", entry.Logger))
+	}
+	if entry.Caller != "" {
+		attributes = append(attributes, otlpAttribute("code.caller", entry.Caller))
+	}
+	for _, field := range entry.Fields {
+		attributes = append(attributes, otlpAttribute(field.Key, field.Value))
+	}
+	if entry.Stack != "" {
+		attributes = append(attributes, otlpAttribute("exception.stacktrace", entry.Stack))
+	}
+
+	var record = map[string]interface{}{
+		"timeUnixNano":         strconv.FormatInt(entry.Time.UnixNano(), 10),
+		"observedTimeUnixNano": strconv.FormatInt(time.Now().UnixNano(), 10),
+		"severityNumber":       otlpSeverity(entry.Level),
+		"severityText":         entry.Level.String(),
+		"body":                 otlpValue(entryBody(entry)),
+	}
+
+	if info := entry.Request; info != nil {
+		for _, attr := range []struct{ key, value string }{
+			{"request.id", info.ID},
+			{"enduser.id", info.UserID},
+			{"ref_error.id", info.RefErrorID},
+			{"http.method", info.Method},
+			{"http.target", info.URI},
+		} {
+			if attr.value != "" {
+				attributes = append(attributes, otlpAttribute(attr.key, attr.value))
+			}
+		}
+		if info.Status > 0 {
+			attributes = append(attributes, otlpAttribute("http.status_code", info.Status))
+		}
+		if info.TraceID != "" {
+			record["traceId"] = info.TraceID
+		}
+		if info.SpanID != "" {
+			record["spanId"] = info.SpanID
+		}
+	}
+	record["attributes"] = attributes
+
+	return record
+}
+
+// entryBody message of the entry or its json values
+func entryBody(entry *Entry) string {
+	if entry.Values == nil {
+		return entry.Message
+	}
+
+	var values = make([]string, 0, len(entry.Values))
+	for _, value := range entry.Values {
+		values = append(values, string(value))
+	}
+
+	return strings.Join(values, " ")
+}
+
+func otlpAttribute(key string, value interface{}) map[string]interface{} {
+	return map[string]interface{}{"key": key, "value": otlpValue(value)}
+}
+
+func otlpValue(value interface{}) map[string]interface{} {
+	switch v := value.(type) {
+	case string:
+		return map[string]interface{}{"stringValue": v}
+	case bool:
+		return map[string]interface{}{"boolValue": v}
+	case int:
+		return map[string]interface{}{"intValue": strconv.FormatInt(int64(v), 10)}
+	case int32:
+		return map[string]interface{}{"intValue": strconv.FormatInt(int64(v), 10)}
+	case int64:
+		return map[string]interface{}{"intValue": strconv.FormatInt(v, 10)}
+	case uint:
+		return map[string]interface{}{"intValue": strconv.FormatUint(uint64(v), 10)}
+	case uint32:
+		return map[string]interface{}{"intValue": strconv.FormatUint(uint64(v), 10)}
+	case uint64:
+		return map[string]interface{}{"intValue": strconv.FormatUint(v, 10)}
+	case float32:
+		return map[string]interface{}{"doubleValue": float64(v)}
+	case float64:
+		return map[string]interface{}{"doubleValue": v}
+	case fmt.Stringer:
+		return map[string]interface{}{"stringValue": v.String()}
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return map[string]interface{}{"stringValue": fmt.Sprint(value)}
+	}
+
+	return map[string]interface{}{"stringValue": string(data)}
+}
+
+func otlpSeverity(level LogLevel) int {
+	switch level {
+	case Debug:
+		return 5
+	case Info:
+		return 9
+	case Warn:
+		return 13
+	case Error:
+		return 17
+	}
+
+	return 0
+}
+
+// recordSpanEvent adds Error entries as a "log" event of the recording span of ctx, with Config.SpanEvents
+func (task *logTask) recordSpanEvent(ctx context.Context) {
+	if task.logLevel != Error || task.logger == nil || !task.logger.config.SpanEvents || !task.logger.Enabled(Error) {
+		return
+	}
+
+	var span = trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+
+	var entry = task.logger.rootLogger().newEntry(task)
+	var attributes = []attribute.KeyValue{
+		attribute.String("log.severity", entry.Level.String()),
+		attribute.String("log.message", entryBody(entry)),
+	}
+	if entry.Caller != "" {
+		attributes = append(attributes, attribute.String("code.caller", entry.Caller))
+	}
+	for _, field := range entry.Fields {
+		attributes = append(attributes, spanAttribute(field))
+	}
+	if entry.Stack != "" {
+		attributes = append(attributes, attribute.String("exception.stacktrace", entry.Stack))
+	}
+
+	span.AddEvent("log", trace.WithAttributes(attributes...))
+}
+
+func spanAttribute(field Field) attribute.KeyValue {
+	switch v := field.Value.(type) {
+	case string:
+		return attribute.String(field.Key, v)
+	case bool:
+		return attribute.Bool(field.Key, v)
+	case int:
+		return attribute.Int(field.Key, v)
+	case int64:
+		return attribute.Int64(field.Key, v)
+	case float64:
+		return attribute.Float64(field.Key, v)
+	}
+
+	var value = otlpValue(field.Value)
+	return attribute.String(field.Key, fmt.Sprint(value["stringValue"]))
+}
@@ -0,0 +1,171 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type recordingSpan struct {
+	trace.Span
+	spanContext trace.SpanContext
+	events      []trace.EventConfig
+	names       []string
+}
+
+func (s *recordingSpan) SpanContext() trace.SpanContext {
+	return s.spanContext
+}
+
+func (s *recordingSpan) IsRecording() bool {
+	return true
+}
+
+func (s *recordingSpan) AddEvent(name string, options ...trace.EventOption) {
+	s.names = append(s.names, name)
+	s.events = append(s.events, trace.NewEventConfig(options...))
+}
+
+func testSpanContext() trace.SpanContext {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+
+	return trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID, TraceFlags: trace.FlagsSampled})
+}
+
+func attributes(event trace.EventConfig) map[string]string {
+	var result = map[string]string{}
+	for _, kv := range event.Attributes() {
+		result[string(kv.Key)] = kv.Value.Emit()
+	}
+	return result
+}
+
+func TestTraceCorrelation(t *testing.T) {
+	var sink = NewRingBuffer(10)
+	var logger = New(&Config{Sync: true, SpanEvents: true, Sinks: []Sink{sink}, Console: log.New(ioutil.Discard, "", 0)})
+	defer logger.Close()
+
+	var span = &recordingSpan{spanContext: testSpanContext()}
+	var ctx = trace.ContextWithSpan(WithContext(context.Background(), &ContextInfo{RequestID: "req-1"}), span)
+
+	logger.InfoWithContext(ctx, "charged")
+	logger.ErrorWithContext(ctx, "charge failed", Any("order", 7))
+
+	var entries = sink.Entries(nil)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "req-1", entries[0].Request.ID)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", entries[0].Request.TraceID)
+	assert.Equal(t, "00f067aa0ba902b7", entries[1].Request.SpanID)
+
+	assert.Equal(t, []string{"log"}, span.names)
+	var attrs = attributes(span.events[0])
+	assert.Equal(t, "ERROR", attrs["log.severity"])
+	assert.Equal(t, "charge failed", attrs["log.message"])
+	assert.Equal(t, "7", attrs["order"])
+	assert.Contains(t, attrs["code.caller"], "otel_test.go")
+
+	// The echo request context carries the span too
+	var e = echo.New()
+	var req = httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+	logger.ErrorWithEchoContext(e.NewContext(req, httptest.NewRecorder()), errors.New("boom"))
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", sink.Entries(nil)[2].Request.TraceID)
+	assert.Len(t, span.events, 2)
+
+	// Named loggers redact with the redactor of the root
+	logger.Named("payments").ErrorWithContext(ctx, "charge failed", Any("password", "hunter2"))
+	assert.Len(t, span.events, 3)
+	assert.Equal(t, RedactedMask, attributes(span.events[2])["password"])
+
+	// Span events are opt-in
+	var other = New(&Config{Sync: true, Console: log.New(ioutil.Discard, "", 0)})
+	defer other.Close()
+	other.ErrorWithContext(ctx, "not recorded")
+	assert.Len(t, span.events, 3)
+}
+
+func TestOTLPSink(t *testing.T) {
+	var mutex sync.Mutex
+	var payloads = []map[string]interface{}{}
+	var server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/logs", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "secret", r.Header.Get("X-Api-Key"))
+
+		var payload map[string]interface{}
+		data, _ := ioutil.ReadAll(r.Body)
+		assert.NoError(t, json.Unmarshal(data, &payload))
+
+		mutex.Lock()
+		payloads = append(payloads, payload)
+		mutex.Unlock()
+	}))
+	defer server.Close()
+
+	var sink = NewOTLPSink(&OTLPConfig{
+		Endpoint:           server.URL + "/v1/logs",
+		Headers:            map[string]string{"X-Api-Key": "secret"},
+		ServiceName:        "api",
+		ResourceAttributes: map[string]string{"deployment.environment": "test"},
+		BatchSize:          2,
+		FlushInterval:      time.Hour,
+	})
+
+	var at = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	sink.Write(&Entry{Level: Warn, Time: at, Message: "slow", Fields: []Field{Any("ms", 1200)}, Request: &EntryRequest{ID: "req-1", TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7"}})
+	sink.Write(&Entry{Level: Info, Time: at, Message: "ok"})
+	sink.Write(&Entry{Level: Error, Time: at, Message: "failed"})
+	assert.NoError(t, sink.Close())
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	assert.Len(t, payloads, 2)
+
+	data, _ := json.Marshal(payloads[0])
+	var payload struct {
+		ResourceLogs []struct {
+			Resource struct {
+				Attributes []map[string]interface{} `json:"attributes"`
+			} `json:"resource"`
+			ScopeLogs []struct {
+				LogRecords []map[string]interface{} `json:"logRecords"`
+			} `json:"scopeLogs"`
+		} `json:"resourceLogs"`
+	}
+	assert.NoError(t, json.Unmarshal(data, &payload))
+
+	var resource = payload.ResourceLogs[0].Resource.Attributes
+	assert.Equal(t, []map[string]interface{}{
+		{"key": "service.name", "value": map[string]interface{}{"stringValue": "api"}},
+		{"key": "deployment.environment", "value": map[string]interface{}{"stringValue": "test"}},
+	}, resource)
+
+	var records = payload.ResourceLogs[0].ScopeLogs[0].LogRecords
+	assert.Len(t, records, 2)
+	assert.Equal(t, "1640995200000000000", records[0]["timeUnixNano"])
+	assert.Equal(t, float64(13), records[0]["severityNumber"])
+	assert.Equal(t, "WARN", records[0]["severityText"])
+	assert.Equal(t, map[string]interface{}{"stringValue": "slow"}, records[0]["body"])
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", records[0]["traceId"])
+	assert.Equal(t, "00f067aa0ba902b7", records[0]["spanId"])
+	assert.Contains(t, records[0]["attributes"], map[string]interface{}{"key": "ms", "value": map[string]interface{}{"intValue": "1200"}})
+	assert.Contains(t, records[0]["attributes"], map[string]interface{}{"key": "request.id", "value": map[string]interface{}{"stringValue": "req-1"}})
+	assert.Nil(t, records[1]["traceId"])
+}
+
+func TestSpanAttribute(t *testing.T) {
+	assert.Equal(t, attribute.Int("n", 1), spanAttribute(Any("n", 1)))
+	assert.Equal(t, attribute.String("m", `{"a":1}`), spanAttribute(Any("m", map[string]int{"a": 1})))
+}
@@ -0,0 +1,166 @@
+package logger
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// OverflowPolicy what to do with new entries when the queue is full
+type OverflowPolicy int
+
+// All overflow policies
+const (
+	// OverflowBlock blocks the caller until the queue has room
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropNewest drops the entry being logged
+	OverflowDropNewest
+	// OverflowDropOldest drops the oldest queued entry to make room for the new one
+	OverflowDropOldest
+	// OverflowSample keeps 1 of every OverflowSampleRate entries (blocking for it) and drops the rest
+	OverflowSample
+)
+
+const (
+	defaultOverflowSampleRate    = 10
+	defaultDroppedReportInterval = time.Minute
+)
+
+// Stats queue and sampling statistics
+type Stats struct {
+	Queued   int    `json:"queued"`
+	Capacity int    `json:"capacity"`
+	Dropped  uint64 `json:"dropped"`
+
+	// Suppressed entries by sampling
+	Suppressed        uint64              `json:"suppressed"`
+	SuppressedByLevel map[LogLevel]uint64 `json:"suppressed_by_level"`
+}
+
+// Stats returns the current queue and sampling statistics
+func (l *Logger) Stats() Stats {
+	if l.root != nil {
+		return l.root.Stats()
+	}
+
+	var suppressed, suppressedByLevel = l.suppressed()
+
+	return Stats{
+		Queued:            len(l.queue),
+		Capacity:          cap(l.queue),
+		Dropped:           atomic.LoadUint64(&l.dropped),
+		Suppressed:        suppressed,
+		SuppressedByLevel: suppressedByLevel,
+	}
+}
+
+func (l *Logger) enqueue(task *logTask) {
+	// The level of the logger the entry was logged with, named loggers may be more verbose than the root
+	if !task.logger.Enabled(task.logLevel) {
+		return
+	}
+
+	// Entries of a buffered request wait for the request to end
+	if task.buffer != nil && task.buffer.hold(task) {
+		return
+	}
+
+	if l.root != nil {
+		l.root.enqueue(task)
+		return
+	}
+
+	if !l.sample(task) {
+		return
+	}
+
+	// Entries logged after Close are discarded
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	if l.context.Err() != nil {
+		return
+	}
+
+	if l.config.Sync {
+		l.syncMutex.Lock()
+		defer l.syncMutex.Unlock()
+		l.process(task)
+		return
+	}
+
+	if l.config.OverflowPolicy == OverflowBlock {
+		l.send(task)
+		return
+	}
+
+	select {
+	case l.queue <- task:
+		return
+	default:
+	}
+
+	switch l.config.OverflowPolicy {
+	case OverflowDropNewest:
+		atomic.AddUint64(&l.dropped, 1)
+
+	case OverflowDropOldest:
+		for {
+			select {
+			case l.queue <- task:
+				return
+			default:
+			}
+
+			select {
+			case oldest := <-l.queue:
+				// The entries before a Flush task are written, it isn't dropped
+				if oldest.flushed != nil {
+					close(oldest.flushed)
+					continue
+				}
+				atomic.AddUint64(&l.dropped, 1)
+			default:
+			}
+		}
+
+	case OverflowSample:
+		var count = atomic.AddUint64(&l.overflowed, 1)
+		if count%uint64(l.config.OverflowSampleRate) == 1 || l.config.OverflowSampleRate == 1 {
+			l.send(task)
+			return
+		}
+		atomic.AddUint64(&l.dropped, 1)
+
+	default:
+		l.send(task)
+	}
+}
+
+// send blocks until the task is queued or the logger is closed
+func (l *Logger) send(task *logTask) {
+	select {
+	case l.queue <- task:
+	case <-l.context.Done():
+	}
+}
+
+func (l *Logger) reportDropped() {
+	var ticker = time.NewTicker(l.config.DroppedReportInterval)
+	defer ticker.Stop()
+
+	var reported uint64
+	for {
+		select {
+		case <-l.context.Done():
+			return
+
+		case <-ticker.C:
+			var dropped = atomic.LoadUint64(&l.dropped)
+			if dropped == reported {
+				continue
+			}
+
+			l.enqueue(l.buildlog(Warn, "", valueTypeInterface, "logger: dropped %d log entries because the queue was full (%d total)", dropped-reported, dropped))
+			reported = dropped
+		}
+	}
+}
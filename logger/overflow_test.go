@@ -0,0 +1,120 @@
+package logger
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type blockingWriter struct {
+	mutex   sync.Mutex
+	entered chan struct{}
+	release chan struct{}
+	lines   []string
+}
+
+func newBlockingWriter() *blockingWriter {
+	return &blockingWriter{
+		entered: make(chan struct{}, 100),
+		release: make(chan struct{}),
+	}
+}
+
+func (w *blockingWriter) Printf(format string, values ...interface{}) {
+	w.entered <- struct{}{}
+	<-w.release
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.lines = append(w.lines, fmt.Sprintf(format, values...))
+}
+
+func (w *blockingWriter) Print(values ...interface{}) {
+	w.Printf("%s", fmt.Sprint(values...))
+}
+
+func TestOverflowDropNewest(t *testing.T) {
+	var writer = newBlockingWriter()
+	var logger = New(&Config{
+		BufferedSize:   2,
+		Writer:         writer,
+		OverflowPolicy: OverflowDropNewest,
+	})
+
+	logger.Info("first")
+	<-writer.entered
+
+	for i := 0; i < 10; i++ {
+		logger.Infof("entry %d", i)
+	}
+
+	var stats = logger.Stats()
+	assert.Equal(t, 2, stats.Queued)
+	assert.Equal(t, 2, stats.Capacity)
+	assert.Equal(t, uint64(8), stats.Dropped)
+
+	close(writer.release)
+}
+
+func TestOverflowDropOldest(t *testing.T) {
+	var writer = newBlockingWriter()
+	var logger = New(&Config{
+		BufferedSize:   2,
+		Writer:         writer,
+		OverflowPolicy: OverflowDropOldest,
+	})
+
+	logger.Info("first")
+	<-writer.entered
+
+	for i := 0; i < 10; i++ {
+		logger.Infof("entry %d", i)
+	}
+
+	assert.Equal(t, uint64(8), logger.Stats().Dropped)
+
+	var oldest = <-logger.queue
+	assert.Equal(t, "entry %d", oldest.format)
+	assert.Equal(t, []interface{}{8}, oldest.values)
+
+	close(writer.release)
+}
+
+func TestOverflowSample(t *testing.T) {
+	var writer = newBlockingWriter()
+	var logger = New(&Config{
+		BufferedSize:       1,
+		Writer:             writer,
+		OverflowPolicy:     OverflowSample,
+		OverflowSampleRate: 5,
+	})
+
+	logger.Info("first")
+	<-writer.entered
+	logger.Info("fill")
+
+	var done = make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 10; i++ {
+			logger.Infof("entry %d", i)
+		}
+	}()
+
+	// Let the writer take one entry each time the producer blocks on a kept entry
+	for _, overflowed := range []uint64{1, 6} {
+		for atomic.LoadUint64(&logger.overflowed) < overflowed {
+			runtime.Gosched()
+		}
+		writer.release <- struct{}{}
+	}
+	<-done
+
+	assert.Equal(t, uint64(8), logger.Stats().Dropped)
+
+	close(writer.release)
+}
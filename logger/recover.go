@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/rs/xid"
+)
+
+// HeaderXRefErrorID response header carrying the ref error ID
+const HeaderXRefErrorID = "X-Ref-Error-ID"
+
+// RecoverConfig recover middleware config
+type RecoverConfig struct {
+	Skipper middleware.Skipper
+
+	// RefErrorIDGenerator generates the ref error ID returned to the client, defaults to xid
+	RefErrorIDGenerator func() string
+}
+
+// DefaultRecoverConfig default recover config
+var DefaultRecoverConfig = RecoverConfig{
+	Skipper:             middleware.DefaultSkipper,
+	RefErrorIDGenerator: generateRefErrorID,
+}
+
+func generateRefErrorID() string {
+	return xid.New().String()
+}
+
+// Recover recovers from panics, logs the stack trace at Error level
+// and returns the ref error ID to the client
+func (l *Logger) Recover() echo.MiddlewareFunc {
+	return l.RecoverWithConfig(DefaultRecoverConfig)
+}
+
+// RecoverWithConfig recover with config
+func (l *Logger) RecoverWithConfig(config RecoverConfig) echo.MiddlewareFunc {
+	if config.Skipper == nil {
+		config.Skipper = DefaultRecoverConfig.Skipper
+	}
+
+	if config.RefErrorIDGenerator == nil {
+		config.RefErrorIDGenerator = DefaultRecoverConfig.RefErrorIDGenerator
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) (returnErr error) {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			defer func() {
+				var r = recover()
+				if r == nil {
+					return
+				}
+
+				// Aborted handlers keep their meaning, net/http closes the connection silently
+				if r == http.ErrAbortHandler {
+					panic(r)
+				}
+
+				var refErrorID = config.RefErrorIDGenerator()
+				SetRefErrorID(c, refErrorID)
+				c.Response().Header().Set(HeaderXRefErrorID, refErrorID)
+
+				var task = l.buildlog(Error, "", valueTypeInterface, "[PANIC RECOVER] %v", r).
+					withEchoContext(c).
+					withStack(string(debug.Stack()))
+				task.requestInfo.status = http.StatusInternalServerError
+				l.enqueue(task)
+
+				returnErr = echo.NewHTTPError(http.StatusInternalServerError, map[string]interface{}{
+					"message":      http.StatusText(http.StatusInternalServerError),
+					"ref_error_id": refErrorID,
+				}).SetInternal(fmt.Errorf("panic: %v", r))
+			}()
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/thaitanloi365/gocore/logger/notifier"
+)
+
+type captureNotifier struct {
+	messages chan *notifier.Message
+}
+
+func (n *captureNotifier) Notify(msg *notifier.Message) []error {
+	n.messages <- msg
+	return nil
+}
+
+func TestRecover(t *testing.T) {
+	var writer = newCaptureWriter()
+	var notifications = &captureNotifier{messages: make(chan *notifier.Message, 1)}
+	var logger = New(&Config{Writer: writer, Notifier: notifications})
+
+	var e = echo.New()
+	e.Use(logger.RecoverWithConfig(RecoverConfig{
+		RefErrorIDGenerator: func() string {
+			return "ref-1"
+		},
+	}))
+	e.GET("/panic", func(c echo.Context) error {
+		panic("boom")
+	})
+
+	var rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, "ref-1", rec.Header().Get(HeaderXRefErrorID))
+
+	var body map[string]interface{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, map[string]interface{}{
+		"message":      "Internal Server Error",
+		"ref_error_id": "ref-1",
+	}, body)
+
+	assert.Regexp(t, `^\[ref-1\] 500 GET /panic .+ ERROR  \[PANIC RECOVER\] boom$`, writer.next(t))
+	assert.Contains(t, writer.next(t), "logger.TestRecover")
+
+	select {
+	case msg := <-notifications.messages:
+		assert.Equal(t, "ERROR", msg.Level)
+		assert.Equal(t, "[PANIC RECOVER] boom", msg.Body)
+		assert.Equal(t, "ref-1", msg.Request.RefErrorID)
+		assert.Equal(t, http.StatusInternalServerError, msg.Request.Status)
+		assert.Contains(t, msg.StackTrace, "runtime/debug.Stack")
+	case <-time.After(time.Second):
+		t.Fatal("notifier not called")
+	}
+}
+
+func TestRecoverAbortHandler(t *testing.T) {
+	var writer = newCaptureWriter()
+	var logger = New(&Config{Writer: writer})
+	defer logger.Close()
+
+	var handler = logger.Recover()(func(c echo.Context) error {
+		panic(http.ErrAbortHandler)
+	})
+
+	var c = echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		handler(c)
+	})
+
+	select {
+	case line := <-writer.lines:
+		t.Fatalf("unexpected line %s", line)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
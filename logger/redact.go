@@ -0,0 +1,354 @@
+package logger
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// RedactedMask replaces redacted values
+const RedactedMask = "[REDACTED]"
+
+// DefaultRedactFields field names masked by default, matched case insensitively
+// ignoring '_' and '-', a field is masked when its name contains one of them
+var DefaultRedactFields = []string{
+	"password",
+	"passwd",
+	"secret",
+	"token",
+	"authorization",
+	"api_key",
+	"card_number",
+	"cvv",
+}
+
+// Free text patterns
+var (
+	EmailPattern      = regexp.MustCompile(`[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}`)
+	PhonePattern      = regexp.MustCompile(`(?:\+\d{1,3}[\s.-]?)?\b\(?\d{2,4}\)?[\s.-]?\d{3,4}[\s.-]?\d{3,4}\b`)
+	CardNumberPattern = regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)
+)
+
+// Redactor masks sensitive values of json values, fields and free text
+type Redactor struct {
+	fields   []string
+	patterns []*regexp.Regexp
+}
+
+// NewRedactor masks values of the fields and text matching the patterns
+func NewRedactor(fields []string, patterns ...*regexp.Regexp) *Redactor {
+	var redactor = &Redactor{
+		patterns: patterns,
+	}
+	for _, field := range fields {
+		redactor.fields = append(redactor.fields, normalizeFieldName(field))
+	}
+
+	return redactor
+}
+
+// WithFields adds field names
+func (r *Redactor) WithFields(fields ...string) *Redactor {
+	for _, field := range fields {
+		r.fields = append(r.fields, normalizeFieldName(field))
+	}
+	return r
+}
+
+// WithPatterns adds free text patterns
+func (r *Redactor) WithPatterns(patterns ...*regexp.Regexp) *Redactor {
+	r.patterns = append(r.patterns, patterns...)
+	return r
+}
+
+// IsSensitive reports whether values of the field must be masked
+func (r *Redactor) IsSensitive(field string) bool {
+	if r == nil {
+		return false
+	}
+
+	var name = normalizeFieldName(field)
+	for _, f := range r.fields {
+		if strings.Contains(name, f) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RedactString masks text matching the patterns
+func (r *Redactor) RedactString(value string) string {
+	if r == nil {
+		return value
+	}
+
+	for _, pattern := range r.patterns {
+		value = pattern.ReplaceAllString(value, RedactedMask)
+	}
+
+	return value
+}
+
+// RedactValue masks sensitive keys and strings matching the patterns of a decoded json value
+func (r *Redactor) RedactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return r.RedactString(v)
+	case map[string]interface{}:
+		for key, item := range v {
+			if r.IsSensitive(key) {
+				v[key] = RedactedMask
+			} else {
+				v[key] = r.RedactValue(item)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = r.RedactValue(item)
+		}
+	}
+
+	return value
+}
+
+// RedactJSON encodes value like ToJSONString and masks sensitive keys and strings matching the patterns
+func (r *Redactor) RedactJSON(value interface{}, pretty bool) string {
+	var encode = ToJSONString
+	if pretty {
+		encode = ToPrettyJSONString
+	}
+
+	if r == nil || (len(r.fields) == 0 && len(r.patterns) == 0) {
+		return encode(value)
+	}
+
+	var decoder = json.NewDecoder(strings.NewReader(ToJSONString(value)))
+	decoder.UseNumber()
+
+	var decoded interface{}
+	if err := decoder.Decode(&decoded); err != nil {
+		return encode(value)
+	}
+
+	return encode(r.RedactValue(decoded))
+}
+
+func (r *Redactor) redactFields(values []interface{}) []interface{} {
+	if r == nil || len(r.fields) == 0 {
+		return values
+	}
+
+	var redacted []interface{}
+	for i, value := range values {
+		if field, ok := value.(Field); ok && r.IsSensitive(field.Key) {
+			if redacted == nil {
+				redacted = append([]interface{}{}, values...)
+			}
+			redacted[i] = Field{Key: field.Key, Value: RedactedMask}
+		}
+	}
+
+	if redacted == nil {
+		return values
+	}
+
+	return redacted
+}
+
+func (r *Redactor) hasPatterns() bool {
+	return r != nil && len(r.patterns) > 0
+}
+
+func normalizeFieldName(name string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "", " ", "").Replace(name))
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	redactTagCache    = sync.Map{}
+)
+
+// redactTags converts value to a json compatible tree with fields tagged `log:"redact"` masked,
+// values without tagged fields are returned as is
+func redactTags(value interface{}) interface{} {
+	if value == nil || !hasRedactTag(reflect.TypeOf(value), map[reflect.Type]bool{}) {
+		return value
+	}
+
+	return redactTagValue(reflect.ValueOf(value))
+}
+
+func hasRedactTag(t reflect.Type, visiting map[reflect.Type]bool) bool {
+	if cached, ok := redactTagCache.Load(t); ok {
+		return cached.(bool)
+	}
+
+	if visiting[t] {
+		return false
+	}
+	visiting[t] = true
+
+	var result = false
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		result = hasRedactTag(t.Elem(), visiting)
+
+	case reflect.Struct:
+		for i := 0; i < t.NumField() && !result; i++ {
+			var field = t.Field(i)
+			result = field.Tag.Get("log") == "redact" || hasRedactTag(field.Type, visiting)
+		}
+	}
+
+	redactTagCache.Store(t, result)
+	return result
+}
+
+func redactTagValue(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+
+	if v.Type().Implements(jsonMarshalerType) || v.Type().Implements(textMarshalerType) {
+		return v.Interface()
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return redactTagValue(v.Elem())
+
+	case reflect.Struct:
+		var result = map[string]interface{}{}
+		redactStructFields(v, result)
+		return result
+
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		var result = map[string]interface{}{}
+		var iter = v.MapRange()
+		for iter.Next() {
+			result[mapKeyString(iter.Key())] = redactTagValue(iter.Value())
+		}
+		return result
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && (v.IsNil() || v.Type().Elem().Kind() == reflect.Uint8) {
+			return v.Interface()
+		}
+		var result = make([]interface{}, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			result[i] = redactTagValue(v.Index(i))
+		}
+		return result
+	}
+
+	return v.Interface()
+}
+
+func redactStructFields(v reflect.Value, result map[string]interface{}) {
+	var t = v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		var field = t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+
+		var name, omitEmpty, skip = parseJSONTag(field)
+		if skip {
+			continue
+		}
+
+		var value = v.Field(i)
+		if field.Anonymous && name == "" {
+			var embedded = value
+			if embedded.Kind() == reflect.Ptr {
+				if embedded.IsNil() {
+					continue
+				}
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				redactStructFields(embedded, result)
+				continue
+			}
+		}
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		if omitEmpty && isEmptyValue(value) {
+			continue
+		}
+
+		if field.Tag.Get("log") == "redact" {
+			result[name] = RedactedMask
+			continue
+		}
+
+		result[name] = redactTagValue(value)
+	}
+}
+
+func parseJSONTag(field reflect.StructField) (name string, omitEmpty bool, skip bool) {
+	var tag = field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+
+	var parts = strings.Split(tag, ",")
+	for _, option := range parts[1:] {
+		if option == "omitempty" {
+			omitEmpty = true
+		}
+	}
+
+	return parts[0], omitEmpty, false
+}
+
+func mapKeyString(key reflect.Value) string {
+	if key.Kind() == reflect.String {
+		return key.String()
+	}
+
+	data, err := json.Marshal(key.Interface())
+	if err != nil {
+		return ""
+	}
+
+	return string(bytes.Trim(data, `"`))
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+
+	return false
+}
@@ -0,0 +1,97 @@
+package logger
+
+import (
+	"io/ioutil"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thaitanloi365/gocore/logger/notifier"
+)
+
+type redactCard struct {
+	Number string `json:"number" log:"redact"`
+	Holder string `json:"holder"`
+}
+
+type redactUser struct {
+	Email    string            `json:"email"`
+	Password string            `json:"password,omitempty" log:"redact"`
+	Note     string            `json:"-"`
+	Cards    []redactCard      `json:"cards"`
+	Extra    map[string]string `json:"extra,omitempty"`
+}
+
+func TestRedactTags(t *testing.T) {
+	var user = &redactUser{
+		Email:    "a@b.com",
+		Password: "secret",
+		Note:     "hidden",
+		Cards:    []redactCard{{Number: "4111111111111111", Holder: "A"}},
+	}
+
+	assert.Equal(t, `{"cards":[{"holder":"A","number":"[REDACTED]"}],"email":"a@b.com","password":"[REDACTED]"}`, ToJSONString(user))
+	assert.Equal(t, `{"Number":"1","Name":"x"}`, ToJSONString(struct {
+		Number string
+		Name   string
+	}{"1", "x"}))
+}
+
+func TestRedactJSON(t *testing.T) {
+	var redactor = NewRedactor(DefaultRedactFields)
+	var request = map[string]interface{}{
+		"username":     "alice",
+		"new_password": "123456",
+		"accessToken":  "abc",
+		"amount":       10,
+		"items": []interface{}{
+			map[string]interface{}{"Authorization": "Bearer abc", "id": 1},
+		},
+	}
+
+	assert.Equal(t, `{"accessToken":"[REDACTED]","amount":10,"items":[{"Authorization":"[REDACTED]","id":1}],"new_password":"[REDACTED]","username":"alice"}`, redactor.RedactJSON(request, false))
+	assert.Equal(t, `"plain"`, redactor.RedactJSON("plain", false))
+}
+
+func TestRedactFieldsAndPatterns(t *testing.T) {
+	var redactor = NewRedactor([]string{"card_number"}, EmailPattern, PhonePattern)
+
+	assert.Equal(t, []interface{}{"login", Any("email", "a@b.com"), Any("CardNumber", RedactedMask)},
+		redactor.redactFields([]interface{}{"login", Any("email", "a@b.com"), Any("CardNumber", "4111")}))
+	assert.Equal(t, "contact [REDACTED] or [REDACTED] at 2022-01-02 15:04:05",
+		redactor.RedactString("contact john.doe@example.com or +84 912 345 678 at 2022-01-02 15:04:05"))
+	assert.Equal(t, "call [REDACTED]", redactor.RedactString("call 0912345678"))
+}
+
+func TestLoggerRedaction(t *testing.T) {
+	var writer = newCaptureWriter()
+	var logger = New(&Config{
+		Writer:   writer,
+		Redactor: NewRedactor(DefaultRedactFields, EmailPattern),
+	})
+
+	logger.Info("login", Any("user", "a@b.com"), Any("password", "123456"))
+	assert.Regexp(t, `INFO .+ login user=\[REDACTED\] password=\[REDACTED\] $`, writer.next(t))
+
+	logger.DebugJSON(map[string]string{"token": "abc"})
+	assert.Regexp(t, `DEBUG .+ {"token":"\[REDACTED\]"} $`, writer.next(t))
+}
+
+func TestNotifierRedaction(t *testing.T) {
+	var notifications = &captureNotifier{messages: make(chan *notifier.Message, 1)}
+	var logger = New(&Config{
+		Sync:     true,
+		Console:  log.New(ioutil.Discard, "", 0),
+		Notifier: notifications,
+		Redactor: NewRedactor(DefaultRedactFields, EmailPattern),
+	})
+	defer logger.Close()
+
+	logger.Errorf("login failed for %s", "john@example.com")
+
+	var msg = <-notifications.messages
+	assert.Equal(t, "login failed for [REDACTED]", strings.TrimSpace(msg.Body))
+	assert.NotContains(t, msg.Title, "john@example.com")
+	assert.NotContains(t, msg.StackTrace, "john@example.com")
+}
@@ -0,0 +1,159 @@
+package logger
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// RequestBufferConfig request buffer middleware config
+type RequestBufferConfig struct {
+	Skipper middleware.Skipper
+
+	// Levels held until the request ends, defaults to Debug and Info
+	Levels []LogLevel
+	// MaxEntries held per request, the oldest are dropped first, defaults to 1000
+	MaxEntries int
+	// FlushFunc decides whether the held entries are logged, defaults to DefaultFlushFunc
+	FlushFunc func(c echo.Context, err error) bool
+}
+
+// DefaultRequestBufferConfig default request buffer config
+var DefaultRequestBufferConfig = RequestBufferConfig{
+	Skipper:    middleware.DefaultSkipper,
+	Levels:     []LogLevel{Debug, Info},
+	MaxEntries: 1000,
+	FlushFunc:  DefaultFlushFunc,
+}
+
+// DefaultFlushFunc flushes when the handler returned an error or the response is 5xx
+func DefaultFlushFunc(c echo.Context, err error) bool {
+	return err != nil || c.Response().Status >= http.StatusInternalServerError
+}
+
+type requestBufferKey struct{}
+
+// requestBuffer entries of one request held in memory
+type requestBuffer struct {
+	mutex   sync.Mutex
+	levels  []LogLevel
+	max     int
+	tasks   []*logTask
+	dropped int
+	closed  bool
+}
+
+// hold keeps the task until the request ends, false when it must be logged now
+func (b *requestBuffer) hold(task *logTask) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.closed || !containsLevel(b.levels, task.logLevel) {
+		return false
+	}
+
+	if len(b.tasks) >= b.max {
+		b.tasks = b.tasks[1:]
+		b.dropped++
+	}
+	b.tasks = append(b.tasks, task)
+
+	return true
+}
+
+// close stops holding entries and returns the held ones
+func (b *requestBuffer) close() ([]*logTask, int) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.closed = true
+	var tasks, dropped = b.tasks, b.dropped
+	b.tasks = nil
+
+	return tasks, dropped
+}
+
+func containsLevel(levels []LogLevel, level LogLevel) bool {
+	for _, l := range levels {
+		if l == level {
+			return true
+		}
+	}
+
+	return false
+}
+
+func requestBufferFromContext(ctx context.Context) *requestBuffer {
+	if ctx == nil {
+		return nil
+	}
+
+	buffer, _ := ctx.Value(requestBufferKey{}).(*requestBuffer)
+	return buffer
+}
+
+// RequestBuffer holds the debug and info entries logged with the request context
+// until the request ends and logs them only when it failed
+func (l *Logger) RequestBuffer() echo.MiddlewareFunc {
+	return l.RequestBufferWithConfig(DefaultRequestBufferConfig)
+}
+
+// RequestBufferWithConfig holds the entries logged with the request context, the
+// *WithEchoContext and *WithContext functions, until the request ends. Entries
+// are logged in order when FlushFunc returns true and dropped otherwise.
+// Register RequestLogger before it so the request line itself isn't held
+func (l *Logger) RequestBufferWithConfig(config RequestBufferConfig) echo.MiddlewareFunc {
+	if config.Skipper == nil {
+		config.Skipper = DefaultRequestBufferConfig.Skipper
+	}
+
+	if config.Levels == nil {
+		config.Levels = DefaultRequestBufferConfig.Levels
+	}
+
+	if config.MaxEntries <= 0 {
+		config.MaxEntries = DefaultRequestBufferConfig.MaxEntries
+	}
+
+	if config.FlushFunc == nil {
+		config.FlushFunc = DefaultRequestBufferConfig.FlushFunc
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			var buffer = &requestBuffer{levels: config.Levels, max: config.MaxEntries}
+			var req = c.Request()
+			c.SetRequest(req.WithContext(context.WithValue(req.Context(), requestBufferKey{}, buffer)))
+
+			var err error
+			defer func() {
+				var tasks, dropped = buffer.close()
+
+				// Panics are failures too, the held entries explain them
+				var recovered = recover()
+				if recovered != nil || config.FlushFunc(c, err) {
+					if dropped > 0 {
+						l.enqueue(l.buildlog(Warn, "", valueTypeInterface, "dropped %d earlier buffered entries", dropped).withEchoContext(c))
+					}
+					for _, task := range tasks {
+						task.logger.enqueue(task)
+					}
+				}
+
+				if recovered != nil {
+					panic(recovered)
+				}
+			}()
+
+			err = next(c)
+			return err
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package logger
+
+import (
+	"errors"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestBuffer(t *testing.T) {
+	var sink = NewRingBuffer(20)
+	var logger = New(&Config{Sync: true, Sinks: []Sink{sink}, Console: log.New(ioutil.Discard, "", 0)})
+	defer logger.Close()
+
+	var e = echo.New()
+	e.Use(logger.RequestBufferWithConfig(RequestBufferConfig{MaxEntries: 2}))
+	e.GET("/ok", func(c echo.Context) error {
+		logger.DebugWithEchoContext(c, "loading")
+		logger.InfoWithContext(c.Request().Context(), "loaded")
+		logger.Warn("not request scoped")
+		return c.NoContent(http.StatusOK)
+	})
+	e.GET("/fail", func(c echo.Context) error {
+		logger.DebugWithEchoContext(c, "step 1")
+		logger.Named("db").DebugWithContext(c.Request().Context(), "step 2")
+		logger.InfoWithEchoContext(c, "step 3")
+		logger.ErrorWithEchoContext(c, "failed")
+		return errors.New("boom")
+	})
+	e.GET("/status", func(c echo.Context) error {
+		logger.InfoWithEchoContext(c, "unavailable")
+		return c.NoContent(http.StatusServiceUnavailable)
+	})
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ok", nil))
+	assert.Equal(t, []string{"not request scoped"}, messages(sink.Entries(nil)))
+
+	// Errors are logged right away, the held entries follow in order
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fail", nil))
+	assert.Equal(t, []string{
+		"not request scoped",
+		"failed",
+		"dropped 1 earlier buffered entries",
+		"[db] step 2",
+		"step 3",
+	}, messages(sink.Entries(nil)))
+	assert.Equal(t, "db", sink.Entries(nil)[3].Logger)
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/status", nil))
+	assert.Equal(t, "unavailable", messages(sink.Entries(nil))[5])
+}
+
+func TestRequestBufferPanic(t *testing.T) {
+	var sink = NewRingBuffer(20)
+	var logger = New(&Config{Sync: true, Sinks: []Sink{sink}, Console: log.New(ioutil.Discard, "", 0)})
+	defer logger.Close()
+
+	var handler = logger.RequestBuffer()(func(c echo.Context) error {
+		logger.InfoWithEchoContext(c, "before panic")
+		panic("boom")
+	})
+
+	var c = echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+	assert.PanicsWithValue(t, "boom", func() {
+		handler(c)
+	})
+	assert.Equal(t, []string{"before panic"}, messages(sink.Entries(nil)))
+
+	// Entries logged after the request ended aren't held
+	logger.InfoWithEchoContext(c, "after")
+	assert.Equal(t, []string{"before panic", "after"}, messages(sink.Entries(nil)))
+}
@@ -0,0 +1,284 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const ringBufferSubscriberSize = 100
+
+const ringBufferHeartbeat = 15 * time.Second
+
+// RingBuffer sink keeping the last entries in memory, add it to Config.Sinks
+// and mount Handler and TailHandler to read them without shell access
+type RingBuffer struct {
+	mutex       sync.RWMutex
+	entries     []*Entry
+	next        int
+	full        bool
+	closed      bool
+	subscribers map[chan *Entry]struct{}
+	encoder     Encoder
+}
+
+// RingBufferFilter filter of the entries, zero values match everything
+type RingBufferFilter struct {
+	// Level minimum level
+	Level     LogLevel
+	RequestID string
+	UserID    string
+	Since     time.Time
+	Until     time.Time
+	// Limit keeps the latest entries
+	Limit int
+}
+
+// NewRingBuffer new ring buffer keeping the last size entries
+func NewRingBuffer(size int) *RingBuffer {
+	if size <= 0 {
+		size = 1000
+	}
+
+	return &RingBuffer{
+		entries:     make([]*Entry, size),
+		subscribers: map[chan *Entry]struct{}{},
+		encoder:     &JSONEncoder{TimeFormat: time.RFC3339Nano},
+	}
+}
+
+// Write implements Sink
+func (b *RingBuffer) Write(entry *Entry) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.closed {
+		return nil
+	}
+
+	b.entries[b.next] = entry
+	b.next = (b.next + 1) % len(b.entries)
+	if b.next == 0 {
+		b.full = true
+	}
+
+	// Slow subscribers miss entries instead of blocking the logger
+	for subscriber := range b.subscribers {
+		select {
+		case subscriber <- entry:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// Close implements Sink, subscribers are closed
+func (b *RingBuffer) Close() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.closed {
+		return nil
+	}
+
+	b.closed = true
+	for subscriber := range b.subscribers {
+		close(subscriber)
+		delete(b.subscribers, subscriber)
+	}
+
+	return nil
+}
+
+// Entries entries matching the filter, oldest first
+func (b *RingBuffer) Entries(filter *RingBufferFilter) []*Entry {
+	if filter == nil {
+		filter = &RingBufferFilter{}
+	}
+
+	b.mutex.RLock()
+	var ordered = make([]*Entry, 0, len(b.entries))
+	if b.full {
+		ordered = append(ordered, b.entries[b.next:]...)
+	}
+	ordered = append(ordered, b.entries[:b.next]...)
+	b.mutex.RUnlock()
+
+	var entries = []*Entry{}
+	for _, entry := range ordered {
+		if filter.Match(entry) {
+			entries = append(entries, entry)
+		}
+	}
+
+	if filter.Limit > 0 && len(entries) > filter.Limit {
+		entries = entries[len(entries)-filter.Limit:]
+	}
+
+	return entries
+}
+
+// Subscribe channel receiving the new entries until cancel or Close is called
+func (b *RingBuffer) Subscribe() (<-chan *Entry, func()) {
+	var subscriber = make(chan *Entry, ringBufferSubscriberSize)
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.closed {
+		close(subscriber)
+		return subscriber, func() {}
+	}
+	b.subscribers[subscriber] = struct{}{}
+
+	return subscriber, func() {
+		b.mutex.Lock()
+		defer b.mutex.Unlock()
+
+		if _, ok := b.subscribers[subscriber]; ok {
+			delete(b.subscribers, subscriber)
+			close(subscriber)
+		}
+	}
+}
+
+// Handler returns the entries as json filtered by the query
+// level, request_id, user_id, since, until (RFC3339) and limit
+func (b *RingBuffer) Handler(c echo.Context) error {
+	filter, err := parseRingBufferFilter(c)
+	if err != nil {
+		return err
+	}
+
+	var entries = b.Entries(filter)
+	var response = make([]json.RawMessage, 0, len(entries))
+	for _, entry := range entries {
+		response = append(response, b.encoder.Encode(entry))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"count":   len(response),
+		"entries": response,
+	})
+}
+
+// TailHandler streams the new entries matching the query as Server-Sent Events,
+// limit sends the latest matching entries first
+func (b *RingBuffer) TailHandler(c echo.Context) error {
+	filter, err := parseRingBufferFilter(c)
+	if err != nil {
+		return err
+	}
+
+	entries, cancel := b.Subscribe()
+	defer cancel()
+
+	var res = c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set("Cache-Control", "no-cache")
+	res.Header().Set("Connection", "keep-alive")
+	res.WriteHeader(http.StatusOK)
+
+	if filter.Limit > 0 {
+		for _, entry := range b.Entries(filter) {
+			b.writeEvent(res, entry)
+		}
+	}
+	res.Flush()
+
+	var heartbeat = time.NewTicker(ringBufferHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request().Context().Done():
+			return nil
+
+		case <-heartbeat.C:
+			fmt.Fprint(res, ": ping\n\n")
+			res.Flush()
+
+		case entry, ok := <-entries:
+			if !ok {
+				return nil
+			}
+			if filter.Match(entry) {
+				b.writeEvent(res, entry)
+				res.Flush()
+			}
+		}
+	}
+}
+
+func (b *RingBuffer) writeEvent(res *echo.Response, entry *Entry) {
+	fmt.Fprintf(res, "data: %s\n\n", b.encoder.Encode(entry))
+}
+
+// Match whether the entry matches the filter
+func (f *RingBufferFilter) Match(entry *Entry) bool {
+	if f.Level != 0 && entry.Level.severity() < f.Level.severity() {
+		return false
+	}
+
+	if !f.Since.IsZero() && entry.Time.Before(f.Since) {
+		return false
+	}
+
+	if !f.Until.IsZero() && entry.Time.After(f.Until) {
+		return false
+	}
+
+	if f.RequestID != "" && (entry.Request == nil || entry.Request.ID != f.RequestID) {
+		return false
+	}
+
+	if f.UserID != "" && (entry.Request == nil || entry.Request.UserID != f.UserID) {
+		return false
+	}
+
+	return true
+}
+
+func parseRingBufferFilter(c echo.Context) (*RingBufferFilter, error) {
+	var filter = &RingBufferFilter{
+		RequestID: c.QueryParam("request_id"),
+		UserID:    c.QueryParam("user_id"),
+	}
+
+	if value := c.QueryParam("level"); value != "" {
+		level, err := ParseLevel(value)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid level %q", value))
+		}
+		filter.Level = level
+	}
+
+	for _, param := range []struct {
+		name  string
+		value *time.Time
+	}{{"since", &filter.Since}, {"until", &filter.Until}} {
+		if value := c.QueryParam(param.name); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid %s %q, use RFC3339", param.name, value))
+			}
+			*param.value = t
+		}
+	}
+
+	if value := c.QueryParam("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 0 {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid limit %q", value))
+		}
+		filter.Limit = limit
+	}
+
+	return filter, nil
+}
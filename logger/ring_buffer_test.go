@@ -0,0 +1,119 @@
+package logger
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func testEntries(buffer *RingBuffer, start time.Time) {
+	buffer.Write(&Entry{Level: Debug, Time: start, Message: "one"})
+	buffer.Write(&Entry{Level: Info, Time: start.Add(time.Minute), Message: "two", Request: &EntryRequest{ID: "req-1", UserID: "alice"}})
+	buffer.Write(&Entry{Level: Error, Time: start.Add(2 * time.Minute), Message: "three", Request: &EntryRequest{ID: "req-1", UserID: "bob"}})
+	buffer.Write(&Entry{Level: Warn, Time: start.Add(3 * time.Minute), Message: "four", Request: &EntryRequest{ID: "req-2", UserID: "alice"}})
+}
+
+func messages(entries []*Entry) []string {
+	var result = []string{}
+	for _, entry := range entries {
+		result = append(result, entry.Message)
+	}
+	return result
+}
+
+func TestRingBufferEntries(t *testing.T) {
+	var buffer = NewRingBuffer(3)
+	var start = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	testEntries(buffer, start)
+
+	assert.Equal(t, []string{"two", "three", "four"}, messages(buffer.Entries(nil)))
+	assert.Equal(t, []string{"three", "four"}, messages(buffer.Entries(&RingBufferFilter{Level: Warn})))
+	assert.Equal(t, []string{"two", "three"}, messages(buffer.Entries(&RingBufferFilter{RequestID: "req-1"})))
+	assert.Equal(t, []string{"two", "four"}, messages(buffer.Entries(&RingBufferFilter{UserID: "alice"})))
+	assert.Equal(t, []string{"three"}, messages(buffer.Entries(&RingBufferFilter{Since: start.Add(2 * time.Minute), Until: start.Add(2 * time.Minute)})))
+	assert.Equal(t, []string{"four"}, messages(buffer.Entries(&RingBufferFilter{Limit: 1})))
+}
+
+func TestRingBufferHandler(t *testing.T) {
+	var buffer = NewRingBuffer(10)
+	testEntries(buffer, time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	var e = echo.New()
+	e.GET("/logs", buffer.Handler)
+
+	var rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/logs?level=info&user_id=alice&since=2022-01-01T00:00:30Z", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response struct {
+		Count   int                      `json:"count"`
+		Entries []map[string]interface{} `json:"entries"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, 2, response.Count)
+	assert.Equal(t, "two", response.Entries[0]["message"])
+	assert.Equal(t, "2022-01-01T00:01:00Z", response.Entries[0]["time"])
+	assert.Equal(t, "WARN", response.Entries[1]["level"])
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/logs?since=yesterday", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "invalid since")
+}
+
+func TestRingBufferTail(t *testing.T) {
+	var buffer = NewRingBuffer(10)
+	var logger = New(&Config{Writer: newCaptureWriter(), Sinks: []Sink{buffer}})
+	defer logger.Close()
+
+	logger.Info("before")
+
+	var e = echo.New()
+	e.GET("/logs/tail", buffer.TailHandler)
+	var server = httptest.NewServer(e)
+	defer server.Close()
+
+	res, err := http.Get(server.URL + "/logs/tail?level=warn")
+	assert.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+
+	var lines = make(chan string)
+	go func() {
+		var scanner = bufio.NewScanner(res.Body)
+		for scanner.Scan() {
+			if line := scanner.Text(); strings.HasPrefix(line, "data: ") {
+				lines <- strings.TrimPrefix(line, "data: ")
+			}
+		}
+		close(lines)
+	}()
+
+	logger.Info("skipped")
+	logger.Error("tailed")
+
+	select {
+	case line := <-lines:
+		var object map[string]interface{}
+		assert.NoError(t, json.Unmarshal([]byte(line), &object))
+		assert.Equal(t, "ERROR", object["level"])
+		assert.Equal(t, "tailed", object["message"])
+	case <-time.After(time.Second):
+		t.Fatal("no event received")
+	}
+
+	buffer.Close()
+	select {
+	case _, ok := <-lines:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("stream not closed")
+	}
+}
@@ -0,0 +1,434 @@
+package logger
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	megabyte            = 1024 * 1024
+	backupTimeFormat    = "2006-01-02T15-04-05.000"
+	compressSuffix      = ".gz"
+	defaultLogFileName  = "app.log"
+	defaultLogFileFlags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+)
+
+// RotatingFile io.WriteCloser rotating by size and time.
+// Rotated files are renamed to name-<time>.ext, compressed and cleaned up in background
+type RotatingFile struct {
+	config *FileLogConfig
+
+	mutex  sync.Mutex
+	file   *os.File
+	size   int64
+	period time.Time
+
+	// pending rotated files not yet handed to the mill goroutine and unmilled ones
+	// not yet passed to OnRotate, kept by the cleanup. Guarded by mutex
+	pending  []string
+	unmilled map[string]bool
+	milling  bool
+
+	millMutex sync.Mutex
+	millCh    chan string
+	millDone  chan struct{}
+
+	// now is replaced in tests
+	now func() time.Time
+}
+
+// NewRotatingFile new rotating file, the file is opened on the first write
+func NewRotatingFile(config *FileLogConfig) *RotatingFile {
+	return &RotatingFile{
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// Filename current file name
+func (r *RotatingFile) Filename() string {
+	if r.config.Filename != "" {
+		return r.config.Filename
+	}
+
+	return filepath.Join(os.TempDir(), defaultLogFileName)
+}
+
+// Write implements io.Writer
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	defer r.mill()
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var maxSize = int64(r.config.MaxSize) * megabyte
+	if maxSize > 0 && int64(len(p)) > maxSize {
+		return 0, fmt.Errorf("write length %d exceeds maximum file size %d", len(p), maxSize)
+	}
+
+	if r.file == nil {
+		if err := r.openExisting(); err != nil {
+			return 0, err
+		}
+	}
+
+	var now = r.now()
+	if r.config.Rotation != RotateNone && !r.periodStart(now).Equal(r.period) {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	} else if maxSize > 0 && r.size+int64(len(p)) > maxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// Rotate closes the current file, renames it and opens a new one
+func (r *RotatingFile) Rotate() error {
+	defer r.mill()
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.rotate()
+}
+
+// Close closes the current file and waits for pending compression, hooks and cleanup
+func (r *RotatingFile) Close() error {
+	r.mutex.Lock()
+	var err = r.closeFile()
+	r.mutex.Unlock()
+
+	r.mill()
+	r.millMutex.Lock()
+	var millCh, millDone = r.millCh, r.millDone
+	r.millCh, r.millDone = nil, nil
+	r.millMutex.Unlock()
+
+	if millCh != nil {
+		close(millCh)
+		<-millDone
+	}
+
+	return err
+}
+
+func (r *RotatingFile) closeFile() error {
+	if r.file == nil {
+		return nil
+	}
+
+	var err = r.file.Close()
+	r.file = nil
+	return err
+}
+
+func (r *RotatingFile) openExisting() error {
+	var name = r.Filename()
+	info, err := os.Stat(name)
+	if os.IsNotExist(err) {
+		return r.openNew()
+	}
+	if err != nil {
+		return err
+	}
+
+	// An existing file of a previous period is rotated on the next write
+	file, err := os.OpenFile(name, defaultLogFileFlags, 0644)
+	if err != nil {
+		return r.openNew()
+	}
+
+	r.file = file
+	r.size = info.Size()
+	r.period = r.periodStart(info.ModTime())
+	return nil
+}
+
+func (r *RotatingFile) openNew() error {
+	var name = r.Filename()
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(name, defaultLogFileFlags|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	r.file = file
+	r.size = 0
+	r.period = r.periodStart(r.now())
+	return nil
+}
+
+func (r *RotatingFile) rotate() error {
+	if err := r.closeFile(); err != nil {
+		return err
+	}
+
+	var name = r.Filename()
+	if _, err := os.Stat(name); err == nil {
+		var backup = r.backupName(name, r.now())
+		if err := os.Rename(name, backup); err != nil {
+			return err
+		}
+		r.pending = append(r.pending, backup)
+		if r.unmilled == nil {
+			r.unmilled = map[string]bool{}
+		}
+		r.unmilled[filepath.Clean(backup)] = true
+	}
+
+	return r.openNew()
+}
+
+func (r *RotatingFile) periodStart(t time.Time) time.Time {
+	switch r.config.Rotation {
+	case RotateHourly:
+		return t.Truncate(time.Hour)
+	case RotateDaily:
+		var year, month, day = t.Date()
+		return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+	}
+
+	return time.Time{}
+}
+
+// backupName name-<time>.ext, rotations within the same millisecond get a
+// name-<time>.<n>.ext counter so no backup, compressed or renamed by OnRotate, is overwritten
+func (r *RotatingFile) backupName(name string, t time.Time) string {
+	var ext = filepath.Ext(name)
+	var prefix = name[:len(name)-len(ext)] + "-" + t.Format(backupTimeFormat)
+
+	var backup = prefix + ext
+	for n := 1; backupExists(backup); n++ {
+		backup = fmt.Sprintf("%s.%d%s", prefix, n, ext)
+	}
+
+	return backup
+}
+
+// backupExists whether the backup or a file named after it, e.g. backup.gz, exists
+func backupExists(backup string) bool {
+	infos, err := ioutil.ReadDir(filepath.Dir(backup))
+	if err != nil {
+		return false
+	}
+
+	var base = filepath.Base(backup)
+	for _, info := range infos {
+		if strings.HasPrefix(info.Name(), base) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// mill hands the pending rotated files to one background goroutine compressing them,
+// running the hook and cleaning up. It runs without the file mutex and only one caller
+// delivers at a time, so a slow OnRotate doesn't block other writers
+func (r *RotatingFile) mill() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.milling {
+		return
+	}
+
+	r.milling = true
+	for len(r.pending) > 0 {
+		var pending = r.pending
+		r.pending = nil
+		r.mutex.Unlock()
+
+		r.millMutex.Lock()
+		if r.millCh == nil {
+			r.millCh = make(chan string, 16)
+			r.millDone = make(chan struct{})
+			go r.millRun(r.millCh, r.millDone)
+		}
+		for _, backup := range pending {
+			r.millCh <- backup
+		}
+		r.millMutex.Unlock()
+
+		r.mutex.Lock()
+	}
+	r.milling = false
+}
+
+func (r *RotatingFile) millRun(millCh chan string, millDone chan struct{}) {
+	defer close(millDone)
+
+	for backup := range millCh {
+		var path = backup
+		if r.config.Compress {
+			if err := compressFile(backup, backup+compressSuffix); err != nil {
+				fmt.Fprintf(os.Stderr, "logger: compress %s error %v\n", backup, err)
+			} else {
+				path = backup + compressSuffix
+			}
+		}
+
+		if r.config.OnRotate != nil {
+			r.config.OnRotate(path)
+		}
+
+		r.mutex.Lock()
+		delete(r.unmilled, filepath.Clean(backup))
+		r.mutex.Unlock()
+
+		if err := r.cleanup(); err != nil {
+			fmt.Fprintf(os.Stderr, "logger: cleanup rotated files error %v\n", err)
+		}
+	}
+}
+
+type backupFile struct {
+	path  string
+	time  time.Time
+	index int
+}
+
+// Backups rotated files, newest first
+func (r *RotatingFile) Backups() ([]string, error) {
+	files, err := r.backups()
+	if err != nil {
+		return nil, err
+	}
+
+	var paths = []string{}
+	for _, file := range files {
+		paths = append(paths, file.path)
+	}
+
+	return paths, nil
+}
+
+func (r *RotatingFile) backups() ([]backupFile, error) {
+	var name = r.Filename()
+	var dir = filepath.Dir(name)
+	var ext = filepath.Ext(name)
+	var prefix = filepath.Base(name[:len(name)-len(ext)]) + "-"
+
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files = []backupFile{}
+	for _, info := range infos {
+		if info.IsDir() {
+			continue
+		}
+
+		var base = strings.TrimSuffix(info.Name(), compressSuffix)
+		if !strings.HasPrefix(base, prefix) || !strings.HasSuffix(base, ext) {
+			continue
+		}
+
+		var stamp, index = base[len(prefix) : len(base)-len(ext)], 0
+		if n := len(backupTimeFormat); len(stamp) > n && stamp[n] == '.' {
+			if index, err = strconv.Atoi(stamp[n+1:]); err != nil {
+				continue
+			}
+			stamp = stamp[:n]
+		}
+
+		t, err := time.ParseInLocation(backupTimeFormat, stamp, time.Local)
+		if err != nil {
+			continue
+		}
+
+		files = append(files, backupFile{path: filepath.Join(dir, info.Name()), time: t, index: index})
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		if files[i].time.Equal(files[j].time) {
+			return files[i].index > files[j].index
+		}
+		return files[i].time.After(files[j].time)
+	})
+
+	return files, nil
+}
+
+func (r *RotatingFile) cleanup() error {
+	if r.config.MaxBackups <= 0 && r.config.MaxAge <= 0 {
+		return nil
+	}
+
+	files, err := r.backups()
+	if err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	var unmilled = make(map[string]bool, len(r.unmilled))
+	for backup := range r.unmilled {
+		unmilled[backup] = true
+	}
+	r.mutex.Unlock()
+
+	// Files waiting for OnRotate, e.g. an upload, are neither removed nor counted
+	var cutoff = r.now().Add(-time.Duration(r.config.MaxAge) * 24 * time.Hour)
+	var kept = 0
+	for _, file := range files {
+		if unmilled[strings.TrimSuffix(file.path, compressSuffix)] {
+			continue
+		}
+
+		var expired = r.config.MaxAge > 0 && file.time.Before(cutoff)
+		var exceeded = r.config.MaxBackups > 0 && kept >= r.config.MaxBackups
+		kept++
+		if expired || exceeded {
+			if err := os.Remove(file.path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func compressFile(src, dst string) error {
+	source, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	target, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	var writer = gzip.NewWriter(target)
+	if _, err = io.Copy(writer, source); err == nil {
+		err = writer.Close()
+	}
+	if e := target.Close(); err == nil {
+		err = e
+	}
+
+	if err != nil {
+		os.Remove(dst)
+		return err
+	}
+
+	return os.Remove(src)
+}
@@ -0,0 +1,211 @@
+package logger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClock is read by the mill goroutine while the test advances it
+type fakeClock struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) Add(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+func TestRotatingFileSize(t *testing.T) {
+	var dir = t.TempDir()
+	var clock = &fakeClock{now: time.Date(2022, 1, 1, 10, 0, 0, 0, time.Local)}
+	var mutex sync.Mutex
+	var rotated = []string{}
+
+	var file = NewRotatingFile(NewFileLog(filepath.Join(dir, "app.log")).
+		WithMaxSize(1).
+		WithMaxBackups(2).
+		WithCompress(false).
+		WithOnRotate(func(path string) {
+			mutex.Lock()
+			rotated = append(rotated, filepath.Base(path))
+			mutex.Unlock()
+		}))
+	file.now = clock.Now
+
+	var chunk = bytes.Repeat([]byte("a"), 600*1024)
+	for i := 0; i < 4; i++ {
+		_, err := file.Write(chunk)
+		assert.NoError(t, err)
+		clock.Add(time.Second)
+	}
+	assert.NoError(t, file.Close())
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	assert.Equal(t, []string{
+		"app-2022-01-01T10-00-01.000.log",
+		"app-2022-01-01T10-00-02.000.log",
+		"app-2022-01-01T10-00-03.000.log",
+	}, rotated)
+
+	backups, err := file.Backups()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "app-2022-01-01T10-00-03.000.log"),
+		filepath.Join(dir, "app-2022-01-01T10-00-02.000.log"),
+	}, backups)
+
+	info, err := os.Stat(filepath.Join(dir, "app.log"))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(chunk)), info.Size())
+
+	_, err = file.Write(bytes.Repeat([]byte("a"), 2*1024*1024))
+	assert.Error(t, err)
+}
+
+func TestRotatingFileDaily(t *testing.T) {
+	var dir = t.TempDir()
+	var clock = &fakeClock{now: time.Date(2022, 1, 1, 23, 59, 0, 0, time.Local)}
+
+	var file = NewRotatingFile(NewFileLog(filepath.Join(dir, "app.log")).
+		WithMaxAge(1).
+		WithRotation(RotateDaily))
+	file.now = clock.Now
+
+	file.Write([]byte("day 1\n"))
+	clock.Add(time.Minute)
+	file.Write([]byte("day 2\n"))
+	clock.Add(72 * time.Hour)
+	file.Write([]byte("day 5\n"))
+	assert.NoError(t, file.Close())
+
+	backups, err := file.Backups()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "app-2022-01-05T00-00-00.000.log.gz")}, backups)
+
+	compressed, err := os.Open(backups[0])
+	assert.NoError(t, err)
+	defer compressed.Close()
+	reader, err := gzip.NewReader(compressed)
+	assert.NoError(t, err)
+	data, _ := ioutil.ReadAll(reader)
+	assert.Equal(t, "day 2\n", string(data))
+
+	current, _ := ioutil.ReadFile(filepath.Join(dir, "app.log"))
+	assert.Equal(t, "day 5\n", string(current))
+}
+
+func TestRotatingFileSameMillisecond(t *testing.T) {
+	var dir = t.TempDir()
+	var clock = &fakeClock{now: time.Date(2022, 1, 1, 10, 0, 0, 0, time.Local)}
+
+	var file = NewRotatingFile(NewFileLog(filepath.Join(dir, "app.log")).WithCompress(false))
+	file.now = clock.Now
+
+	for _, line := range []string{"first\n", "second\n", "third\n"} {
+		file.Write([]byte(line))
+		assert.NoError(t, file.Rotate())
+	}
+	assert.NoError(t, file.Close())
+
+	backups, err := file.Backups()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "app-2022-01-01T10-00-00.000.2.log"),
+		filepath.Join(dir, "app-2022-01-01T10-00-00.000.1.log"),
+		filepath.Join(dir, "app-2022-01-01T10-00-00.000.log"),
+	}, backups)
+
+	data, _ := ioutil.ReadFile(backups[2])
+	assert.Equal(t, "first\n", string(data))
+}
+
+func TestRotatingFileSlowHook(t *testing.T) {
+	var release = make(chan struct{})
+	var file = NewRotatingFile(NewFileLog(filepath.Join(t.TempDir(), "app.log")).
+		WithCompress(false).
+		WithOnRotate(func(path string) {
+			<-release
+		}))
+
+	// Rotations beyond the mill queue don't block writers of other goroutines
+	var rotated = make(chan struct{})
+	go func() {
+		for i := 0; i < 20; i++ {
+			file.Write([]byte("line\n"))
+			file.Rotate()
+		}
+		close(rotated)
+	}()
+
+	var written = make(chan struct{})
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		file.Write([]byte("line\n"))
+		close(written)
+	}()
+
+	select {
+	case <-written:
+	case <-time.After(time.Second):
+		t.Fatal("write blocked by OnRotate")
+	}
+
+	close(release)
+	<-rotated
+	assert.NoError(t, file.Close())
+}
+
+func TestLoggerFileSink(t *testing.T) {
+	var dir = t.TempDir()
+	var logger = New(&Config{
+		File:                  NewFileLog(filepath.Join(dir, "app.log")).WithFormat(FormatJSON),
+		WriteFileExceptLevels: []LogLevel{Debug},
+	})
+
+	logger.Debug("skipped")
+	logger.Info("hello", Any("id", 1))
+	assert.NoError(t, logger.Close())
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "app.log"))
+	assert.NoError(t, err)
+
+	var lines = strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Len(t, lines, 1)
+	assert.Contains(t, lines[0], `"level":"INFO"`)
+	assert.Contains(t, lines[0], `"message":"hello"`)
+	assert.Contains(t, lines[0], `"id":1`)
+
+	// Logging after Close is a no-op
+	logger.Info("closed")
+}
+
+func TestLoggerKeepsConfig(t *testing.T) {
+	var config = &Config{File: NewFileLog(filepath.Join(t.TempDir(), "app.log"))}
+
+	var logger = New(config)
+	assert.NoError(t, logger.Close())
+
+	assert.Empty(t, config.File.TimeFormat)
+	assert.Empty(t, config.DateFormat)
+	assert.Zero(t, config.BufferedSize)
+}
@@ -0,0 +1,101 @@
+package logger
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sync/atomic"
+	"time"
+)
+
+const samplerCounters = 4096
+
+// SamplingConfig logs the first Initial entries of a message template per Tick,
+// then 1 of every Thereafter, Thereafter 0 drops the rest
+type SamplingConfig struct {
+	Initial    int
+	Thereafter int
+	// Tick defaults to 1 second
+	Tick time.Duration
+}
+
+type sampleCounter struct {
+	resetAt int64
+	count   uint64
+}
+
+func (c *sampleCounter) inc(now time.Time, tick time.Duration) uint64 {
+	var nanos = now.UnixNano()
+	var resetAt = atomic.LoadInt64(&c.resetAt)
+	if resetAt > nanos {
+		return atomic.AddUint64(&c.count, 1)
+	}
+
+	atomic.StoreUint64(&c.count, 1)
+	if !atomic.CompareAndSwapInt64(&c.resetAt, resetAt, nanos+tick.Nanoseconds()) {
+		// Another goroutine reset the counter first
+		return atomic.AddUint64(&c.count, 1)
+	}
+
+	return 1
+}
+
+type sampler struct {
+	config     SamplingConfig
+	counters   [samplerCounters]sampleCounter
+	suppressed uint64
+}
+
+func newSampler(config SamplingConfig) *sampler {
+	if config.Tick <= 0 {
+		config.Tick = time.Second
+	}
+
+	return &sampler{config: config}
+}
+
+func (s *sampler) allow(template string, now time.Time) bool {
+	var hash = fnv.New32a()
+	hash.Write([]byte(template))
+
+	var n = s.counters[hash.Sum32()%samplerCounters].inc(now, s.config.Tick)
+	if n <= uint64(s.config.Initial) {
+		return true
+	}
+
+	if s.config.Thereafter > 0 && (n-uint64(s.config.Initial))%uint64(s.config.Thereafter) == 0 {
+		return true
+	}
+
+	atomic.AddUint64(&s.suppressed, 1)
+	return false
+}
+
+// template identifies the message of the task, the format or the first value
+func (task *logTask) template() string {
+	if task.format != "" || len(task.values) == 0 {
+		return task.format
+	}
+
+	return fmt.Sprint(task.values[0])
+}
+
+func (l *Logger) sample(task *logTask) bool {
+	var sampler = l.samplers[task.logLevel]
+	if sampler == nil {
+		return true
+	}
+
+	return sampler.allow(task.template(), time.Now())
+}
+
+func (l *Logger) suppressed() (uint64, map[LogLevel]uint64) {
+	var total uint64
+	var byLevel = map[LogLevel]uint64{}
+	for level, sampler := range l.samplers {
+		var count = atomic.LoadUint64(&sampler.suppressed)
+		byLevel[level] = count
+		total += count
+	}
+
+	return total, byLevel
+}
@@ -0,0 +1,51 @@
+package logger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSampler(t *testing.T) {
+	var sampler = newSampler(SamplingConfig{Initial: 2, Thereafter: 3})
+	var now = time.Now()
+
+	var allowed = []bool{}
+	for i := 0; i < 8; i++ {
+		allowed = append(allowed, sampler.allow("hot path %d", now))
+	}
+	assert.Equal(t, []bool{true, true, false, false, true, false, false, true}, allowed)
+	assert.Equal(t, uint64(4), sampler.suppressed)
+
+	assert.True(t, sampler.allow("other message", now))
+	assert.True(t, sampler.allow("hot path %d", now.Add(time.Second)))
+}
+
+func TestLoggerSampling(t *testing.T) {
+	var writer = newCaptureWriter()
+	var logger = New(&Config{
+		BufferedSize: 100,
+		Writer:       writer,
+		Sampling: map[LogLevel]*SamplingConfig{
+			Debug: {Initial: 3, Tick: time.Hour},
+		},
+	})
+
+	for i := 0; i < 10; i++ {
+		logger.Debugf("request %d", i)
+		logger.Info("not sampled")
+	}
+
+	var stats = logger.Stats()
+	assert.Equal(t, uint64(7), stats.Suppressed)
+	assert.Equal(t, map[LogLevel]uint64{Debug: 7}, stats.SuppressedByLevel)
+
+	var debug = 0
+	for i := 0; i < 13; i++ {
+		if line := writer.next(t); line[len(line)-len("not sampled "):] != "not sampled " {
+			debug++
+		}
+	}
+	assert.Equal(t, 3, debug)
+}
@@ -0,0 +1,160 @@
+package logger
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Sink receives every processed log entry
+type Sink interface {
+	Write(entry *Entry) error
+	Close() error
+}
+
+// Entry log entry passed to sinks, sensitive values are already redacted
+type Entry struct {
+	Level LogLevel
+	// Logger name of the named logger, empty for the root
+	Logger  string
+	Time    time.Time
+	Caller  string
+	Message string
+	Fields  []Field
+	// Values json values of the *JSON log functions
+	Values []json.RawMessage
+	Stack  string
+
+	Request *EntryRequest
+}
+
+// EntryRequest request the entry was logged for
+type EntryRequest struct {
+	ID         string `json:"id,omitempty"`
+	UserID     string `json:"user_id,omitempty"`
+	RefErrorID string `json:"ref_error_id,omitempty"`
+	Method     string `json:"method,omitempty"`
+	URI        string `json:"uri,omitempty"`
+	Status     int    `json:"status,omitempty"`
+	TraceID    string `json:"trace_id,omitempty"`
+	SpanID     string `json:"span_id,omitempty"`
+}
+
+func (entry *Entry) hasErrorStack() bool {
+	for _, field := range entry.Fields {
+		if detail, ok := field.Value.(*ErrorDetail); ok && detail.Stack == entry.Stack {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (l *Logger) newEntry(task *logTask) *Entry {
+	var values = l.redactor.redactFields(task.values)
+	var entry = &Entry{
+		Level:  task.logLevel,
+		Logger: task.name,
+		Time:   task.at,
+		Caller: task.caller,
+		Stack:  l.redactor.RedactString(task.stack),
+	}
+
+	for _, field := range append(task.fields(values), task.loggerFields...) {
+		if l.redactor.IsSensitive(field.Key) {
+			field.Value = RedactedMask
+		}
+		if value, ok := field.Value.(string); ok {
+			field.Value = l.redactor.RedactString(value)
+		}
+		entry.Fields = append(entry.Fields, field)
+	}
+
+	switch task.valueType {
+	case valueTypeJSON:
+		entry.Values = []json.RawMessage{}
+		for _, value := range task.values {
+			entry.Values = append(entry.Values, json.RawMessage(l.redactor.RedactJSON(value, false)))
+		}
+	default:
+		entry.Message = l.redactor.RedactString(task.message(values))
+		if task.prefix != "" {
+			entry.Message = strings.TrimSpace(task.prefix + " " + entry.Message)
+		}
+	}
+
+	if info := task.requestInfo; info != nil {
+		entry.Request = &EntryRequest{
+			ID:         info.reqID,
+			UserID:     info.userID,
+			RefErrorID: info.refErrorID,
+			Method:     info.method,
+			URI:        info.uri,
+			Status:     info.status,
+			TraceID:    info.traceID,
+			SpanID:     info.spanID,
+		}
+	}
+
+	return entry
+}
+
+// levelSink skips entries of the except levels
+type levelSink struct {
+	Sink
+	except []LogLevel
+}
+
+func (s *levelSink) Write(entry *Entry) error {
+	for _, level := range s.except {
+		if level == entry.Level {
+			return nil
+		}
+	}
+
+	return s.Sink.Write(entry)
+}
+
+// WriterSink writes encoded entries to an io.Writer, e.g. os.Stdout
+type WriterSink struct {
+	mutex   sync.Mutex
+	writer  io.Writer
+	encoder Encoder
+}
+
+// NewWriterSink new writer sink
+func NewWriterSink(writer io.Writer, format LogFormat, timeFormat string) *WriterSink {
+	if timeFormat == "" {
+		timeFormat = defaultDateFormat
+	}
+
+	return &WriterSink{
+		writer:  writer,
+		encoder: NewEncoder(format, timeFormat),
+	}
+}
+
+// Write implements Sink
+func (s *WriterSink) Write(entry *Entry) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	_, err := s.writer.Write(append(s.encoder.Encode(entry), '\n'))
+	return err
+}
+
+// Close implements Sink, the writer is closed when it is an io.Closer other than stdout and stderr
+func (s *WriterSink) Close() error {
+	if s.writer == os.Stdout || s.writer == os.Stderr {
+		return nil
+	}
+
+	if closer, ok := s.writer.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+//go:build go1.21
+// +build go1.21
+
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SlogHandler slog.Handler writing to a Logger, request info is read from the context
+type SlogHandler struct {
+	logger *Logger
+	fields []Field
+	group  string
+}
+
+// SlogHandler slog.Handler writing to l
+func (l *Logger) SlogHandler() *SlogHandler {
+	return &SlogHandler{logger: l}
+}
+
+// Slog slog.Logger writing to l
+func (l *Logger) Slog() *slog.Logger {
+	return slog.New(l.SlogHandler())
+}
+
+// Enabled implements slog.Handler
+func (h *SlogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.logger.Enabled(slogLevel(level))
+}
+
+// Handle implements slog.Handler
+func (h *SlogHandler) Handle(ctx context.Context, record slog.Record) error {
+	var fields = h.fields[:len(h.fields):len(h.fields)]
+	record.Attrs(func(attr slog.Attr) bool {
+		fields = appendAttr(fields, h.group, attr)
+		return true
+	})
+
+	var task = h.logger.adapterTask(slogLevel(record.Level), callerPC(record.PC), record.Message, fields)
+	if !record.Time.IsZero() {
+		task.at = record.Time.In(h.logger.config.TimeLocation)
+	}
+	if ctx != nil {
+		task.withContext(ctx)
+	}
+	h.logger.enqueue(task)
+
+	return nil
+}
+
+// WithAttrs implements slog.Handler
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var handler = *h
+	handler.fields = h.fields[:len(h.fields):len(h.fields)]
+	for _, attr := range attrs {
+		handler.fields = appendAttr(handler.fields, h.group, attr)
+	}
+
+	return &handler
+}
+
+// WithGroup implements slog.Handler, keys of the group are prefixed with name.
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	var handler = *h
+	handler.group = h.group + name + "."
+	return &handler
+}
+
+// appendAttr appends attr as fields, groups are flattened with dotted keys
+func appendAttr(fields []Field, prefix string, attr slog.Attr) []Field {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return fields
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		var group = prefix
+		if attr.Key != "" {
+			group = prefix + attr.Key + "."
+		}
+		for _, a := range attr.Value.Group() {
+			fields = appendAttr(fields, group, a)
+		}
+		return fields
+	}
+
+	if err, ok := attr.Value.Any().(error); ok {
+		return append(fields, errorField(prefix+attr.Key, err))
+	}
+
+	return append(fields, Any(prefix+attr.Key, attr.Value.Any()))
+}
+
+func slogLevel(level slog.Level) LogLevel {
+	switch {
+	case level < slog.LevelInfo:
+		return Debug
+	case level < slog.LevelWarn:
+		return Info
+	case level < slog.LevelError:
+		return Warn
+	}
+
+	return Error
+}
+
+var _ slog.Handler = (*SlogHandler)(nil)
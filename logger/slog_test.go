@@ -0,0 +1,36 @@
+//go:build go1.21
+// +build go1.21
+
+package logger
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlogHandler(t *testing.T) {
+	var writer = newCaptureWriter()
+	var logger = New(&Config{Writer: writer, Format: FormatJSON, Level: Info})
+	defer logger.Close()
+
+	var slogger = logger.Slog().With("service", "api").WithGroup("http")
+	slogger.Debug("skipped")
+
+	var ctx = WithContext(context.Background(), &ContextInfo{RequestID: "req-1"})
+	var expected = nextLine()
+	slogger.ErrorContext(ctx, "request failed", "status", 502, slog.Group("upstream", "host", "pay"), "err", errors.New("timeout"))
+
+	var object = nextJSON(t, writer)
+	assert.Equal(t, "ERROR", object["level"])
+	assert.Equal(t, "request failed", object["message"])
+	assert.Equal(t, "api", object["service"])
+	assert.Equal(t, float64(502), object["http.status"])
+	assert.Equal(t, "pay", object["http.upstream.host"])
+	assert.Equal(t, "timeout", object["http.err"].(map[string]interface{})["message"])
+	assert.Equal(t, "req-1", object["request"].(map[string]interface{})["id"])
+	assert.Contains(t, object["caller"], expected)
+}
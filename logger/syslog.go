@@ -0,0 +1,265 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Syslog facilities of RFC 5424
+const (
+	FacilityUser   = 1
+	FacilityDaemon = 3
+	FacilityAuth   = 4
+	FacilityLocal0 = 16
+	FacilityLocal1 = 17
+	FacilityLocal2 = 18
+	FacilityLocal3 = 19
+	FacilityLocal4 = 20
+	FacilityLocal5 = 21
+	FacilityLocal6 = 22
+	FacilityLocal7 = 23
+)
+
+// DefaultSyslogSDID structured data ID of the request info, 32473 is the example enterprise number of RFC 5612
+const DefaultSyslogSDID = "request@32473"
+
+const syslogTimeFormat = "2006-01-02T15:04:05.000000Z07:00"
+
+// SyslogConfig syslog sink config
+type SyslogConfig struct {
+	// Network udp, tcp, unix (stream) or unixgram, udp by default
+	Network string
+	// Address host:port, or the socket path for unix networks, e.g. /dev/log
+	Address string
+
+	// Facility defaults to FacilityUser
+	Facility int
+	// AppName defaults to the executable name, Hostname to os.Hostname
+	AppName  string
+	Hostname string
+	// SDID structured data ID of the request info, defaults to DefaultSyslogSDID
+	SDID string
+
+	// Format of MSG, text writes the message and key=value fields, json writes the json line
+	Format LogFormat
+
+	// Timeout of dialing and writing, 5s by default
+	Timeout time.Duration
+}
+
+// SyslogSink writes RFC 5424 messages to a syslog server, the connection is
+// dialed on the first write and redialed once when a write fails
+type SyslogSink struct {
+	mutex   sync.Mutex
+	config  *SyslogConfig
+	conn    net.Conn
+	encoder Encoder
+	pid     string
+}
+
+// NewSyslogSink new syslog sink
+func NewSyslogSink(config *SyslogConfig) *SyslogSink {
+	var cfg = *config
+	if cfg.Network == "" {
+		cfg.Network = "udp"
+	}
+	if cfg.Facility <= 0 {
+		cfg.Facility = FacilityUser
+	}
+	if cfg.AppName == "" {
+		cfg.AppName = filepath.Base(os.Args[0])
+	}
+	if cfg.Hostname == "" {
+		cfg.Hostname, _ = os.Hostname()
+	}
+	if cfg.SDID == "" {
+		cfg.SDID = DefaultSyslogSDID
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+
+	var sink = &SyslogSink{
+		config: &cfg,
+		pid:    strconv.Itoa(os.Getpid()),
+	}
+	if cfg.Format == FormatJSON {
+		sink.encoder = &JSONEncoder{TimeFormat: syslogTimeFormat}
+	}
+
+	return sink
+}
+
+// Write implements Sink
+func (s *SyslogSink) Write(entry *Entry) error {
+	var message = s.Format(entry)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var err = s.write(message)
+	if err != nil && s.conn != nil {
+		// The server may have restarted, retry once with a new connection
+		s.conn.Close()
+		s.conn = nil
+		err = s.write(message)
+	}
+
+	return err
+}
+
+// Close implements Sink
+func (s *SyslogSink) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.conn == nil {
+		return nil
+	}
+
+	var err = s.conn.Close()
+	s.conn = nil
+
+	return err
+}
+
+// Format RFC 5424 message of the entry: <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID [SD] MSG
+func (s *SyslogSink) Format(entry *Entry) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "<%d>1 %s %s %s %s %s ",
+		s.config.Facility*8+SyslogSeverity(entry.Level),
+		entry.Time.Format(syslogTimeFormat),
+		syslogHeader(s.config.Hostname, 255),
+		syslogHeader(s.config.AppName, 48),
+		syslogHeader(s.pid, 128),
+		syslogHeader(entry.Logger, 32),
+	)
+
+	s.writeStructuredData(&buf, entry.Request)
+
+	buf.WriteByte(' ')
+	if s.encoder != nil {
+		buf.Write(s.encoder.Encode(entry))
+	} else {
+		buf.WriteString(syslogText(entry))
+	}
+
+	return buf.Bytes()
+}
+
+func (s *SyslogSink) write(message []byte) error {
+	if s.conn == nil {
+		conn, err := net.DialTimeout(s.config.Network, s.config.Address, s.config.Timeout)
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+	}
+
+	s.conn.SetWriteDeadline(time.Now().Add(s.config.Timeout))
+
+	switch s.config.Network {
+	case "tcp", "tcp4", "tcp6", "unix":
+		// Octet counting framing of RFC 6587, messages may contain newlines
+		message = append([]byte(strconv.Itoa(len(message))+" "), message...)
+	}
+
+	_, err := s.conn.Write(message)
+	return err
+}
+
+func (s *SyslogSink) writeStructuredData(buf *bytes.Buffer, info *EntryRequest) {
+	var params = [][2]string{}
+	if info != nil {
+		for _, param := range [][2]string{
+			{"id", info.ID},
+			{"user_id", info.UserID},
+			{"ref_error_id", info.RefErrorID},
+			{"method", info.Method},
+			{"uri", info.URI},
+			{"trace_id", info.TraceID},
+			{"span_id", info.SpanID},
+		} {
+			if param[1] != "" {
+				params = append(params, param)
+			}
+		}
+		if info.Status > 0 {
+			params = append(params, [2]string{"status", strconv.Itoa(info.Status)})
+		}
+	}
+
+	if len(params) == 0 {
+		buf.WriteByte('-')
+		return
+	}
+
+	buf.WriteByte('[')
+	buf.WriteString(s.config.SDID)
+	for _, param := range params {
+		fmt.Fprintf(buf, ` %s="%s"`, param[0], syslogParamEscaper.Replace(param[1]))
+	}
+	buf.WriteByte(']')
+}
+
+// SyslogSeverity RFC 5424 severity of the level
+func SyslogSeverity(level LogLevel) int {
+	switch level {
+	case Debug:
+		return 7
+	case Info:
+		return 6
+	case Warn:
+		return 4
+	case Error:
+		return 3
+	}
+
+	return 5
+}
+
+var syslogParamEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+
+// syslogHeader header field, printable US-ASCII without spaces or "-" when empty
+func syslogHeader(value string, max int) string {
+	var header = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return '_'
+		}
+		return r
+	}, value)
+
+	if header == "" {
+		return "-"
+	}
+	if len(header) > max {
+		header = header[:max]
+	}
+
+	return header
+}
+
+// syslogText message, json values, fields and stack of the entry
+func syslogText(entry *Entry) string {
+	var parts = []string{}
+	if body := entryBody(entry); body != "" {
+		parts = append(parts, body)
+	}
+	for _, field := range entry.Fields {
+		parts = append(parts, field.String())
+	}
+
+	var text = strings.Join(parts, " ")
+	if entry.Stack != "" {
+		text += "\n" + entry.Stack
+	}
+
+	return text
+}
@@ -0,0 +1,107 @@
+package logger
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testSyslogEntry() *Entry {
+	return &Entry{
+		Level:   Error,
+		Logger:  "payments",
+		Time:    time.Date(2022, 1, 2, 3, 4, 5, 6000, time.UTC),
+		Caller:  "payments/charge.go:42",
+		Message: "charge failed",
+		Fields:  []Field{Any("order_id", 7)},
+		Stack:   "goroutine 1\nmain.go:10",
+		Request: &EntryRequest{ID: "req-1", UserID: `a"b]`, Status: 500},
+	}
+}
+
+func TestSyslogFormat(t *testing.T) {
+	var sink = NewSyslogSink(&SyslogConfig{Address: "localhost:514", Facility: FacilityLocal0, AppName: "api", Hostname: "web 1"})
+	var pid = strconv.Itoa(os.Getpid())
+
+	assert.Equal(t,
+		`<131>1 2022-01-02T03:04:05.000006Z web_1 api `+pid+` payments [request@32473 id="req-1" user_id="a\"b\]" status="500"] charge failed order_id=7`+"\ngoroutine 1\nmain.go:10",
+		string(sink.Format(testSyslogEntry())))
+
+	assert.Equal(t,
+		`<14>1 2022-01-02T03:04:05.000006Z web_1 api `+pid+` - - started`,
+		string(NewSyslogSink(&SyslogConfig{AppName: "api", Hostname: "web 1"}).Format(&Entry{Level: Info, Time: time.Date(2022, 1, 2, 3, 4, 5, 6000, time.UTC), Message: "started"})))
+
+	assert.Equal(t, 7, SyslogSeverity(Debug))
+	assert.Equal(t, 4, SyslogSeverity(Warn))
+}
+
+func TestSyslogUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	var sink = NewSyslogSink(&SyslogConfig{Address: conn.LocalAddr().String(), AppName: "api", Format: FormatJSON})
+	defer sink.Close()
+	assert.NoError(t, sink.Write(&Entry{Level: Warn, Time: time.Now(), Message: "slow"}))
+
+	var buf = make([]byte, 2048)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(buf[:n]), "<12>1 "))
+	assert.Contains(t, string(buf[:n]), ` - - {"level":"WARN","message":"slow"`)
+}
+
+func TestSyslogTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+
+	var messages = make(chan string, 2)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var reader = bufio.NewReader(conn)
+		for {
+			size, err := reader.ReadString(' ')
+			if err != nil {
+				return
+			}
+			n, _ := strconv.Atoi(strings.TrimSpace(size))
+			var message = make([]byte, n)
+			if _, err := io.ReadFull(reader, message); err != nil {
+				return
+			}
+			messages <- string(message)
+		}
+	}()
+
+	var sink = NewSyslogSink(&SyslogConfig{Network: "tcp", Address: listener.Addr().String(), AppName: "api"})
+	defer sink.Close()
+	assert.NoError(t, sink.Write(testSyslogEntry()))
+	assert.NoError(t, sink.Write(&Entry{Level: Debug, Time: time.Now(), Message: "done"}))
+
+	// Octet counting keeps the multiline stack in one message
+	assert.True(t, strings.HasSuffix(<-messages, "charge failed order_id=7\ngoroutine 1\nmain.go:10"))
+	assert.True(t, strings.HasSuffix(<-messages, "- - done"))
+}
+
+func TestSyslogDialError(t *testing.T) {
+	var sink = NewSyslogSink(&SyslogConfig{Network: "unix", Address: filepath.Join(t.TempDir(), "missing.sock")})
+	assert.Error(t, sink.Write(&Entry{Level: Info, Time: time.Now()}))
+	assert.NoError(t, sink.Close())
+}
+
+// readJournalFields decodes a native journal protocol datagram
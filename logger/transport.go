@@ -0,0 +1,256 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// TransportConfig logging transport config
+type TransportConfig struct {
+	// Base transport the requests are sent with, defaults to http.DefaultTransport
+	Base http.RoundTripper
+	// Skipper skips logging the request when it returns true
+	Skipper func(req *http.Request) bool
+
+	// LevelFunc maps the response status to a level, defaults to DefaultStatusLevel, failed requests are Error
+	LevelFunc func(status int) LogLevel
+
+	// MaxRequestBodySize and MaxResponseBodySize capture bodies up to the size in bytes, 0 disables capture
+	MaxRequestBodySize  int
+	MaxResponseBodySize int
+
+	// RedactQueryParams query parameters masked in addition to the sensitive fields of the logger redactor
+	RedactQueryParams []string
+
+	// RequestIDHeader carries the request ID of the context to the called service, defaults to X-Request-ID
+	RequestIDHeader string
+}
+
+// DefaultTransportConfig default logging transport config
+var DefaultTransportConfig = TransportConfig{
+	LevelFunc: DefaultStatusLevel,
+	RedactQueryParams: []string{
+		"key", "sig", "signature", "code", "client_secret",
+		"access_token", "refresh_token", "id_token", "token", "api_key", "apikey",
+	},
+	RequestIDHeader: echo.HeaderXRequestID,
+}
+
+// transportCallerPackages packages skipped when resolving the caller of an outbound request
+var transportCallerPackages = []string{
+	"net/http",
+	"github.com/parnurzeal/gorequest",
+	"github.com/sendgrid/rest",
+	"github.com/sendgrid/sendgrid-go",
+	"github.com/kevinburke/rest",
+	"github.com/kevinburke/twilio-go",
+}
+
+// Transport logs every outbound request sent with base, e.g. &http.Client{Transport: logger.Transport(nil)}
+func (l *Logger) Transport(base http.RoundTripper) http.RoundTripper {
+	var config = DefaultTransportConfig
+	config.Base = base
+	return l.TransportWithConfig(config)
+}
+
+// TransportWithConfig logs every outbound request with config
+func (l *Logger) TransportWithConfig(config TransportConfig) http.RoundTripper {
+	if config.Base == nil {
+		config.Base = http.DefaultTransport
+	}
+
+	if config.LevelFunc == nil {
+		config.LevelFunc = DefaultTransportConfig.LevelFunc
+	}
+
+	if config.RequestIDHeader == "" {
+		config.RequestIDHeader = DefaultTransportConfig.RequestIDHeader
+	}
+
+	return &loggingTransport{logger: l, redactor: l.rootLogger().redactor, config: config}
+}
+
+// HTTPClient http client logging its requests with Transport
+func (l *Logger) HTTPClient() *http.Client {
+	return &http.Client{Transport: l.Transport(nil)}
+}
+
+type loggingTransport struct {
+	logger   *Logger
+	redactor *Redactor
+	config   TransportConfig
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = t.propagate(req)
+
+	if t.config.Skipper != nil && t.config.Skipper(req) {
+		return t.config.Base.RoundTrip(req)
+	}
+
+	var caller = callerOutside(2, transportCallerPackages...)
+
+	var reqBody []byte
+	if t.config.MaxRequestBodySize > 0 && req.Body != nil && req.Body != http.NoBody {
+		reqBody, _ = ioutil.ReadAll(io.LimitReader(req.Body, int64(t.config.MaxRequestBodySize)))
+		// Shallow copy, RoundTrippers must not modify the caller request
+		req = req.WithContext(req.Context())
+		req.Body = &readCloser{
+			Reader: io.MultiReader(bytes.NewReader(reqBody), req.Body),
+			Closer: req.Body,
+		}
+	}
+
+	var start = time.Now()
+	res, err := t.config.Base.RoundTrip(req)
+	var latency = time.Since(start)
+
+	var format = "%s %s"
+	var values = []interface{}{req.Method, t.redactURL(req.URL)}
+	var level = Error
+	if err == nil {
+		level = t.config.LevelFunc(res.StatusCode)
+		format += " status=%d"
+		values = append(values, res.StatusCode)
+	}
+
+	format += " latency=%s"
+	values = append(values, latency)
+
+	if err != nil {
+		format += " error=%v"
+		values = append(values, err)
+	}
+
+	if len(reqBody) > 0 {
+		format += " request_body=%s"
+		values = append(values, t.redactBody(reqBody, req.Header.Get(echo.HeaderContentType)))
+	}
+
+	if err == nil && t.config.MaxResponseBodySize > 0 && res.Body != nil {
+		// The body is peeked and given back to the caller unchanged
+		var resBody, _ = ioutil.ReadAll(io.LimitReader(res.Body, int64(t.config.MaxResponseBodySize)))
+		res.Body = &readCloser{
+			Reader: io.MultiReader(bytes.NewReader(resBody), res.Body),
+			Closer: res.Body,
+		}
+		if len(resBody) > 0 {
+			format += " response_body=%s"
+			values = append(values, t.redactBody(resBody, res.Header.Get(echo.HeaderContentType)))
+		}
+	}
+
+	t.logger.enqueue(t.logger.buildlog(level, caller, valueTypeInterface, format, values...).withContext(req.Context()))
+
+	return res, err
+}
+
+// propagate sets the request ID and traceparent headers from the request info of the context
+func (t *loggingTransport) propagate(req *http.Request) *http.Request {
+	var info = FromContext(req.Context())
+	if info == nil {
+		return req
+	}
+
+	var headers = map[string]string{}
+	if info.RequestID != "" && req.Header.Get(t.config.RequestIDHeader) == "" {
+		headers[t.config.RequestIDHeader] = info.RequestID
+	}
+	if info.TraceID != "" && info.SpanID != "" && req.Header.Get(HeaderTraceParent) == "" {
+		headers[HeaderTraceParent] = fmt.Sprintf("00-%s-%s-01", info.TraceID, info.SpanID)
+	}
+
+	if len(headers) == 0 {
+		return req
+	}
+
+	// RoundTrippers must not modify the request of the caller
+	req = req.Clone(req.Context())
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	return req
+}
+
+func (t *loggingTransport) isSensitive(key string) bool {
+	for _, name := range t.config.RedactQueryParams {
+		if strings.EqualFold(name, key) {
+			return true
+		}
+	}
+
+	return t.redactor.IsSensitive(key)
+}
+
+// redactURL url with the sensitive query parameters and the password masked
+func (t *loggingTransport) redactURL(u *url.URL) string {
+	var redacted = *u
+	if _, ok := redacted.User.Password(); ok {
+		redacted.User = url.UserPassword(redacted.User.Username(), RedactedMask)
+	}
+
+	if redacted.RawQuery != "" {
+		redacted.RawQuery = t.redactQuery(redacted.RawQuery)
+	}
+
+	return redacted.String()
+}
+
+func (t *loggingTransport) redactQuery(rawQuery string) string {
+	var parts = strings.Split(rawQuery, "&")
+	for i, part := range parts {
+		var pair = strings.SplitN(part, "=", 2)
+		key, err := url.QueryUnescape(pair[0])
+		if err != nil {
+			key = pair[0]
+		}
+		if len(pair) == 2 && t.isSensitive(key) {
+			parts[i] = pair[0] + "=" + url.QueryEscape(RedactedMask)
+		}
+	}
+
+	return strings.Join(parts, "&")
+}
+
+// redactBody masks sensitive keys of json and form bodies, the keys of truncated
+// json bodies are matched with jsonPairPattern
+func (t *loggingTransport) redactBody(body []byte, contentType string) string {
+	if strings.HasPrefix(contentType, echo.MIMEApplicationForm) {
+		return t.redactor.RedactString(t.redactQuery(string(body)))
+	}
+
+	if json.Valid(body) {
+		var decoder = json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+
+		var decoded interface{}
+		if err := decoder.Decode(&decoded); err == nil {
+			return ToJSONString(t.redactor.RedactValue(decoded))
+		}
+	}
+
+	var redacted = jsonPairPattern.ReplaceAllStringFunc(string(body), func(pair string) string {
+		var match = jsonPairPattern.FindStringSubmatch(pair)
+		if !t.isSensitive(match[1]) {
+			return pair
+		}
+		return pair[:len(pair)-len(match[2])] + `"` + RedactedMask + `"`
+	})
+
+	return t.redactor.RedactString(redacted)
+}
+
+// jsonPairPattern "key": value pairs of json text, the value may be cut by the truncation
+var jsonPairPattern = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"\s*:\s*("(?:[^"\\]|\\.)*"?|[^\s,\]}"{[]+)`)
@@ -3,6 +3,7 @@ package logger
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 )
 
 // Writer interface
@@ -22,6 +23,38 @@ const (
 	Error
 )
 
+// String level name
+func (level LogLevel) String() string {
+	switch level {
+	case Debug:
+		return "DEBUG"
+	case Warn:
+		return "WARN"
+	case Info:
+		return "INFO"
+	case Error:
+		return "ERROR"
+	}
+
+	return fmt.Sprintf("LEVEL(%d)", int(level))
+}
+
+// ParseLevel level of the name, case insensitive
+func ParseLevel(name string) (LogLevel, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return Debug, nil
+	case "warn", "warning":
+		return Warn, nil
+	case "info":
+		return Info, nil
+	case "error":
+		return Error, nil
+	}
+
+	return 0, fmt.Errorf("unknown log level %q", name)
+}
+
 // Colors
 var (
 	Black   = Color("\033[1;30m%s\033[0m")
@@ -29,7 +62,7 @@ var (
 	Green   = Color("\033[1;32m%s\033[0m")
 	Blue    = Color("\033[1;34m%s\033[0m")
 	Yellow  = Color("\033[1;33m%s\033[0m")
-	Purple  = Color("\033[1;34m%s\033[0m")
+	Purple  = Color("\033[1;38;5;93m%s\033[0m")
 	Magenta = Color("\033[1;35m%s\033[0m")
 	Teal    = Color("\033[1;36m%s\033[0m")
 	White   = Color("\033[1;37m%s\033[0m")
@@ -44,9 +77,9 @@ func Color(colorString string) func(...interface{}) string {
 	return sprint
 }
 
-// ToJSONString convert to json string
+// ToJSONString convert to json string, struct fields tagged `log:"redact"` are masked
 func ToJSONString(value interface{}) string {
-	data, err := json.Marshal(value)
+	data, err := json.Marshal(redactTags(value))
 	if err != nil {
 		return fmt.Errorf("Couldn't marshal error %v", err).Error()
 	}
@@ -54,9 +87,9 @@ func ToJSONString(value interface{}) string {
 	return string(data)
 }
 
-// ToPrettyJSONString convert to json string
+// ToPrettyJSONString convert to json string, struct fields tagged `log:"redact"` are masked
 func ToPrettyJSONString(value interface{}) string {
-	data, err := json.MarshalIndent(value, "", "    ")
+	data, err := json.MarshalIndent(redactTags(value), "", "    ")
 	if err != nil {
 		return fmt.Errorf("Couldn't marshal error %v", err).Error()
 	}
@@ -0,0 +1,136 @@
+package logger
+
+import (
+	"runtime"
+	"sort"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// ZapCore zapcore.Core writing to a Logger, zap logger names are named loggers
+type ZapCore struct {
+	logger *Logger
+	fields []Field
+}
+
+// ZapCore zapcore.Core writing to l
+func (l *Logger) ZapCore() *ZapCore {
+	return &ZapCore{logger: l}
+}
+
+// Zap zap.Logger writing to l
+func (l *Logger) Zap(options ...zap.Option) *zap.Logger {
+	return zap.New(l.ZapCore(), options...)
+}
+
+// Enabled implements zapcore.Core
+func (c *ZapCore) Enabled(level zapcore.Level) bool {
+	return c.logger.Enabled(zapLevel(level))
+}
+
+// With implements zapcore.Core
+func (c *ZapCore) With(fields []zapcore.Field) zapcore.Core {
+	var fs = c.fields[:len(c.fields):len(c.fields)]
+	return &ZapCore{
+		logger: c.logger,
+		fields: append(fs, zapFields(fields)...),
+	}
+}
+
+// Check implements zapcore.Core
+func (c *ZapCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+
+	return checked
+}
+
+// Write implements zapcore.Core, fatal entries close the logger to flush the queue before zap exits
+func (c *ZapCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	var l = c.logger
+	if entry.LoggerName != "" {
+		l = l.Named(entry.LoggerName)
+	}
+
+	var caller string
+	if entry.Caller.Defined {
+		caller = formatFrame(runtime.Frame{
+			File:     entry.Caller.File,
+			Line:     entry.Caller.Line,
+			Function: entry.Caller.Function,
+		})
+	} else {
+		caller = callerOutside(2, "go.uber.org/zap", "go.uber.org/zap/zapcore")
+	}
+
+	var fs = c.fields[:len(c.fields):len(c.fields)]
+	var task = l.adapterTask(zapLevel(entry.Level), caller, entry.Message, append(fs, zapFields(fields)...))
+	task.at = entry.Time.In(l.config.TimeLocation)
+	if entry.Stack != "" {
+		task.withStack(entry.Stack)
+	}
+	l.enqueue(task)
+
+	if entry.Level == zapcore.FatalLevel {
+		return l.Close()
+	}
+
+	return nil
+}
+
+// Sync implements zapcore.Core, it waits for the queued entries like Flush
+func (c *ZapCore) Sync() error {
+	c.logger.Flush()
+	return nil
+}
+
+func zapFields(fields []zapcore.Field) []Field {
+	var result = make([]Field, 0, len(fields))
+	var namespace string
+	for _, field := range fields {
+		switch field.Type {
+		case zapcore.SkipType:
+			continue
+		case zapcore.NamespaceType:
+			namespace += field.Key + "."
+			continue
+		case zapcore.ErrorType:
+			if err, ok := field.Interface.(error); ok {
+				result = append(result, errorField(namespace+field.Key, err))
+				continue
+			}
+		}
+
+		var encoder = zapcore.NewMapObjectEncoder()
+		field.AddTo(encoder)
+
+		var keys = make([]string, 0, len(encoder.Fields))
+		for key := range encoder.Fields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			result = append(result, Any(namespace+key, encoder.Fields[key]))
+		}
+	}
+
+	return result
+}
+
+func zapLevel(level zapcore.Level) LogLevel {
+	switch {
+	case level < zapcore.InfoLevel:
+		return Debug
+	case level == zapcore.InfoLevel:
+		return Info
+	case level == zapcore.WarnLevel:
+		return Warn
+	}
+
+	return Error
+}
+
+var _ zapcore.Core = (*ZapCore)(nil)
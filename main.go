@@ -87,6 +87,22 @@ func testLoggerWithLumberjack() {
 	}
 }
 
+func testLoggerWithFileConfig() {
+	var logger = logger.New(&logger.Config{
+		BufferedSize: 100,
+		File: logger.NewFileLog("logs/app.log").
+			WithRotation(logger.RotateDaily).
+			WithFormat(logger.FormatJSON),
+	})
+	defer logger.Close()
+
+	for i := 0; i < 10; i++ {
+		logger.Debugf("count %d \n", i)
+		logger.Debug("count sssss", i, "asdfasdf")
+		time.Sleep(time.Second)
+	}
+}
+
 func testStorageClient() {
 	var st = storage.New(storage.DefaultConfig)
 
@@ -281,8 +297,8 @@ func testLogWithHTTP() {
 	}))
 	e.GET("/success", func(c echo.Context) error {
 		fmt.Println(c.Request().Header)
-		c.Set(logger.RefErrorIDKey, "aaaaa")
-		c.Set(logger.UserIDKey, "1111")
+		logger.SetRefErrorID(c, "aaaaa")
+		logger.SetUserID(c, "1111")
 		var user = map[string]interface{}{
 			"name": "Test",
 		}
@@ -0,0 +1,177 @@
+package s3
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+// UploadRotatedLogFileParams upload rotated log file params
+type UploadRotatedLogFileParams struct {
+	UploadToBucket string
+	// Prefix of the keys, files are uploaded to <prefix>/<yyyy>/<mm>/<dd>/<hostname>/<file>.gz
+	Prefix string
+	// Hostname defaults to os.Hostname
+	Hostname string
+	ACL      string
+
+	// MaxRetries defaults to 3, RetryBackoff defaults to 1 second and doubles after every attempt
+	MaxRetries   int
+	RetryBackoff time.Duration
+
+	KeepFileAfterUpload bool
+}
+
+// pendingUploadSuffix marks rotated files whose upload failed, the suffix keeps them
+// out of the MaxBackups and MaxAge cleanup of the rotating file until they are uploaded
+const pendingUploadSuffix = ".pending"
+
+// RotatedLogUploader returns a logger.FileLogConfig OnRotate hook uploading every rotated file.
+// The local file is only removed after the upload is confirmed, failed uploads are renamed
+// to <file>.pending and retried on the next rotation
+func (s3 *S3) RotatedLogUploader(params UploadRotatedLogFileParams) func(path string) {
+	return func(path string) {
+		pending, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "*"+pendingUploadSuffix))
+		for _, file := range append(pending, path) {
+			if _, err := s3.UploadRotatedLogFile(params, file); err != nil {
+				s3.logger.Printf("Upload rotated log file %s error: %v\n", file, err)
+				if !strings.HasSuffix(file, pendingUploadSuffix) {
+					if err := os.Rename(file, file+pendingUploadSuffix); err != nil {
+						s3.logger.Printf("Keep rotated log file %s error %+v\n", file, err)
+					}
+				}
+				continue
+			}
+
+			if params.KeepFileAfterUpload && strings.HasSuffix(file, pendingUploadSuffix) {
+				if err := os.Rename(file, strings.TrimSuffix(file, pendingUploadSuffix)); err != nil {
+					s3.logger.Printf("Restore rotated log file %s error %+v\n", file, err)
+				}
+			}
+		}
+	}
+}
+
+// UploadRotatedLogFile uploads one closed log file gzipped, retrying on failure
+func (s3 *S3) UploadRotatedLogFile(params UploadRotatedLogFileParams, file string) (string, error) {
+	info, err := os.Stat(file)
+	if err != nil {
+		return "", err
+	}
+
+	var hostname = params.Hostname
+	if hostname == "" {
+		hostname, _ = os.Hostname()
+	}
+
+	var key = rotatedLogFileKey(params.Prefix, hostname, file, info.ModTime())
+	var maxRetries = params.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = 3
+	}
+	var backoff = params.RetryBackoff
+	if backoff <= 0 {
+		backoff = time.Second
+	}
+
+	var location string
+	err = retry(maxRetries, backoff, func() error {
+		location, err = s3.uploadGzipFile(params, file, key)
+		if err != nil {
+			s3.logger.Printf("Upload %s to s3 error: %v\n", file, err)
+		}
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	s3.logger.Printf("%s is uploaded to s3 at %s\n", file, location)
+
+	if params.KeepFileAfterUpload == false {
+		if err := os.Remove(file); err != nil {
+			s3.logger.Printf("Removed log file %s error %+v\n", file, err)
+		}
+	}
+
+	return location, nil
+}
+
+func (s3 *S3) uploadGzipFile(params UploadRotatedLogFileParams, file, key string) (string, error) {
+	originFile, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer originFile.Close()
+
+	var body io.Reader = originFile
+	if !strings.HasSuffix(strings.TrimSuffix(file, pendingUploadSuffix), ".gz") {
+		reader, writer := io.Pipe()
+		go func() {
+			gw := gzip.NewWriter(writer)
+			_, err := io.Copy(gw, originFile)
+			if err == nil {
+				err = gw.Close()
+			}
+			writer.CloseWithError(err)
+		}()
+		defer reader.Close()
+		body = reader
+	}
+
+	sess, err := s3.NewSession()
+	if err != nil {
+		return "", err
+	}
+
+	var input = &s3manager.UploadInput{
+		Body:            body,
+		Bucket:          aws.String(params.UploadToBucket),
+		Key:             aws.String(key),
+		ContentType:     aws.String("text/plain"),
+		ContentEncoding: aws.String("gzip"),
+	}
+	if params.ACL != "" {
+		input.ACL = aws.String(params.ACL)
+	}
+
+	result, err := s3manager.NewUploader(sess).Upload(input)
+	if err != nil {
+		return "", err
+	}
+
+	return result.Location, nil
+}
+
+func rotatedLogFileKey(prefix, hostname, file string, modTime time.Time) string {
+	var name = strings.TrimSuffix(filepath.Base(file), pendingUploadSuffix)
+	if !strings.HasSuffix(name, ".gz") {
+		name = name + ".gz"
+	}
+
+	var utc = modTime.UTC()
+	return strings.TrimPrefix(path.Join(prefix, utc.Format("2006"), utc.Format("01"), utc.Format("02"), hostname, name), "/")
+}
+
+func retry(maxRetries int, backoff time.Duration, fn func() error) error {
+	var err error
+	for attempt := 0; ; attempt++ {
+		if err = fn(); err == nil {
+			return nil
+		}
+
+		if attempt >= maxRetries {
+			return fmt.Errorf("failed after %d attempts: %w", attempt+1, err)
+		}
+
+		time.Sleep(backoff << uint(attempt))
+	}
+}
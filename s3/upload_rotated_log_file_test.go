@@ -0,0 +1,89 @@
+package s3
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thaitanloi365/gocore/logger"
+)
+
+func TestRotatedLogFileKey(t *testing.T) {
+	var modTime = time.Date(2022, 1, 5, 23, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "logs/2022/01/05/api-1/app-2022-01-05T23-00-00.000.log.gz", rotatedLogFileKey("logs", "api-1", "/var/log/app-2022-01-05T23-00-00.000.log", modTime))
+	assert.Equal(t, "2022/01/05/api-1/app.log.gz", rotatedLogFileKey("", "api-1", "app.log.gz", modTime))
+}
+
+func TestRetry(t *testing.T) {
+	var attempts = 0
+	var err = retry(2, time.Millisecond, func() error {
+		attempts++
+		if attempts < 3 {
+			return errors.New("timeout")
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+
+	attempts = 0
+	err = retry(1, time.Millisecond, func() error {
+		attempts++
+		return errors.New("timeout")
+	})
+	assert.EqualError(t, err, "failed after 2 attempts: timeout")
+	assert.Equal(t, 2, attempts)
+}
+
+type captureLogger struct {
+	mutex sync.Mutex
+	lines []string
+}
+
+func (l *captureLogger) Printf(format string, v ...interface{}) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.lines = append(l.lines, fmt.Sprintf(format, v...))
+}
+
+func TestRotatedLogUploaderFailure(t *testing.T) {
+	var dir = t.TempDir()
+	var output = &captureLogger{}
+	// Uploads fail without a region, before any request is sent
+	var client = New(&Config{Logger: output})
+
+	var file = logger.NewRotatingFile(logger.NewFileLog(filepath.Join(dir, "app.log")).
+		WithMaxBackups(1).
+		WithCompress(false).
+		WithOnRotate(client.RotatedLogUploader(UploadRotatedLogFileParams{
+			UploadToBucket: "logs",
+			MaxRetries:     1,
+			RetryBackoff:   time.Millisecond,
+		})))
+
+	for i := 0; i < 3; i++ {
+		file.Write([]byte("line\n"))
+		assert.NoError(t, file.Rotate())
+	}
+	assert.NoError(t, file.Close())
+
+	// Every failed upload survives the MaxBackups cleanup and is retried on the next rotation
+	pending, err := filepath.Glob(filepath.Join(dir, "app-*.log"+pendingUploadSuffix))
+	assert.NoError(t, err)
+	assert.Len(t, pending, 3)
+
+	var retried = 0
+	for _, line := range output.lines {
+		if strings.HasPrefix(line, "Upload rotated log file") && strings.Contains(line, pendingUploadSuffix) {
+			retried++
+		}
+	}
+	assert.Equal(t, 3, retried)
+}
@@ -2,10 +2,12 @@ package sendgrid
 
 import (
 	"log"
+	"net/http"
 	"os"
 	"strings"
 
 	jsoniter "github.com/json-iterator/go"
+	"github.com/sendgrid/rest"
 	"github.com/sendgrid/sendgrid-go"
 	"github.com/sendgrid/sendgrid-go/helpers/mail"
 )
@@ -18,6 +20,9 @@ type Config struct {
 	BccAddresses string
 	CcAddresses  string
 	Logger       Logger
+
+	// HTTPClient sends the API requests, e.g. logger.HTTPClient() to log them
+	HTTPClient *http.Client
 }
 
 // Email mail and name
@@ -136,7 +141,7 @@ func (mailer *Mailer) SendMail(params SendMailParams) error {
 	var request = sendgrid.GetRequest(apiKey, "/v3/mail/send", "https://api.sendgrid.com")
 	request.Method = "POST"
 	request.Body = mail.GetRequestBody(m)
-	response, err := sendgrid.API(request)
+	response, err := mailer.client().Send(request)
 
 	if err != nil {
 		mailer.logger.Printf("Send mail error: %v\n", err)
@@ -157,6 +162,14 @@ func (mailer *Mailer) SendMail(params SendMailParams) error {
 	return nil
 }
 
+func (mailer *Mailer) client() *rest.Client {
+	if mailer.config.HTTPClient != nil {
+		return &rest.Client{HTTPClient: mailer.config.HTTPClient}
+	}
+
+	return sendgrid.DefaultClient
+}
+
 func (mailer *Mailer) splitEmails(mailList, mailTo string) []*mail.Email {
 	var cc = []*mail.Email{}
 	if mailList != "" {
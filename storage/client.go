@@ -2,7 +2,10 @@ package storage
 
 import (
 	"crypto/tls"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"net/http"
 	"os"
 	"path"
 
@@ -24,9 +27,10 @@ type ClientAuthConfig struct {
 // Client http client
 type Client struct {
 	*gorequest.SuperAgent
-	baseURL string
-	storage *Storage
-	auth    *ClientAuthConfig
+	baseURL    string
+	storage    *Storage
+	auth       *ClientAuthConfig
+	httpClient *http.Client
 }
 
 // NewClient init
@@ -40,6 +44,14 @@ func newClient(storage *Storage, baseURL string) *Client {
 	}
 }
 
+// WithHTTPClient sends UploadFiles and DeleteFiles with client. Clients without a
+// Transport use the agent transport skipping TLS verification as before, wrap it to
+// log the requests, e.g. &http.Client{Transport: logger.Transport(c.Transport)}
+func (c *Client) WithHTTPClient(client *http.Client) *Client {
+	c.httpClient = client
+	return c
+}
+
 // UploadFileResult result
 type UploadFileResult struct {
 	Name        string `json:"name"`
@@ -82,9 +94,8 @@ func (c *Client) UploadFiles(params ...UploadFileParams) ([]*UploadFileResult, e
 	}
 
 	var uploadedFiles []string
-	_, _, errs := req.EndStruct(&uploadedFiles)
-	if errs != nil && len(errs) > 0 {
-		return nil, errs[0]
+	if err := c.end(req, &uploadedFiles); err != nil {
+		return nil, err
 	}
 
 	var results []*UploadFileResult
@@ -108,12 +119,49 @@ func (c *Client) DeleteFiles(files ...string) error {
 	var req = c.Delete(url)
 	req = c.setReqAuth(req)
 
-	_, _, errs := req.Send(form).End()
-	if len(errs) > 0 {
-		return errs[0]
+	return c.end(req.Send(form), nil)
+}
+
+// end sends the request with the http client when set, the json response is decoded
+// to v. Errors match gorequest: transport and decoding errors, the status isn't checked
+func (c *Client) end(req *gorequest.SuperAgent, v interface{}) error {
+	if c.httpClient == nil {
+		var errs []error
+		if v != nil {
+			_, _, errs = req.EndStruct(v)
+		} else {
+			_, _, errs = req.End()
+		}
+		if len(errs) > 0 {
+			return errs[0]
+		}
+		return nil
+	}
+
+	httpReq, err := req.MakeRequest()
+	if err != nil {
+		return err
+	}
+
+	var client = c.httpClient
+	if client.Transport == nil {
+		var copied = *client
+		copied.Transport = c.Transport
+		client = &copied
+	}
+
+	res, err := client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil || v == nil {
+		return err
 	}
 
-	return nil
+	return json.Unmarshal(body, v)
 }
 
 func (c *Client) setReqAuth(req *gorequest.SuperAgent) *gorequest.SuperAgent {
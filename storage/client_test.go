@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countingTransport struct {
+	base  http.RoundTripper
+	count int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.count++
+	return t.base.RoundTrip(req)
+}
+
+func TestClientHTTPClient(t *testing.T) {
+	var server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.URL.Path == "/upload" {
+			w.Write([]byte(`["a.png"]`))
+			return
+		}
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	var transport *countingTransport
+	for _, withClient := range []func(c *Client){
+		func(c *Client) {},
+		func(c *Client) {
+			c.WithHTTPClient(&http.Client{})
+		},
+		func(c *Client) {
+			transport = &countingTransport{base: c.Transport}
+			c.WithHTTPClient(&http.Client{Transport: transport})
+		},
+	} {
+		// Self signed certificates are accepted and the status isn't checked in every mode
+		var client = newClient(nil, server.URL)
+		withClient(client)
+
+		results, err := client.UploadFiles()
+		assert.NoError(t, err)
+		assert.Equal(t, server.URL+"/a.png", results[0].URL)
+
+		assert.NoError(t, client.DeleteFiles("a.png"))
+
+		// Error bodies fail to decode
+		client.baseURL = server.URL + "/broken"
+		_, err = client.UploadFiles()
+		assert.Error(t, err)
+	}
+	assert.Equal(t, 3, transport.count)
+}
@@ -1,6 +1,7 @@
 package twilio
 
 import (
+	"net/http"
 	"net/url"
 
 	"github.com/kevinburke/rest"
@@ -18,12 +19,15 @@ type Config struct {
 	AccountSID  string
 	AuthToken   string
 	SenderPhone string
+
+	// HTTPClient sends the API requests, e.g. logger.HTTPClient() to log them
+	HTTPClient *http.Client
 }
 
 // New init
 func New(config *Config) *Twilio {
 	return &Twilio{
-		Client: twilio.NewClient(config.AccountSID, config.AuthToken, nil),
+		Client: twilio.NewClient(config.AccountSID, config.AuthToken, config.HTTPClient),
 		config: config,
 	}
 }